@@ -0,0 +1,256 @@
+package climacell
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EndpointAlerts is the ClimaCell API's endpoint for managing alerts, which
+// notify you when weather conditions at your saved locations cross a
+// threshold. Requests for a single alert go to paths under alerts/{id}, but
+// are labeled with EndpointAlerts in logs, metrics, and traces.
+const EndpointAlerts Endpoint = "alerts"
+
+// The operators an AlertCondition can compare a field's value with its
+// threshold by.
+const (
+	OperatorGreaterThan        = ">"
+	OperatorGreaterThanOrEqual = ">="
+	OperatorLessThan           = "<"
+	OperatorLessThanOrEqual    = "<="
+	OperatorEqual              = "=="
+)
+
+// AlertCondition is a threshold on a weather field that triggers an alert,
+// such as wind gust above 20 m/s.
+type AlertCondition struct {
+	// Field is the weather field to check, such as "wind_gust".
+	Field string `json:"field"`
+	// Operator is how the field's value is compared with Value, such as
+	// OperatorGreaterThan.
+	Operator string `json:"operator"`
+	// Value is the threshold the field's value is compared with.
+	Value float64 `json:"value"`
+	// Units, if nonempty, are the units Value is in, such as "m/s".
+	Units string `json:"units,omitempty"`
+}
+
+func (cond AlertCondition) String() string {
+	s := fmt.Sprintf("%s %s %g", cond.Field, cond.Operator, cond.Value)
+	if cond.Units != "" {
+		s += " " + cond.Units
+	}
+	return s
+}
+
+// Alert is a set of conditions the ClimaCell API watches for at the locations
+// linked to it.
+type Alert struct {
+	// ID is the alert's ID.
+	ID string `json:"id"`
+	// Name is the alert's name.
+	Name string `json:"name"`
+	// Conditions are the thresholds that trigger the alert. The alert is
+	// triggered when all of its conditions are met.
+	Conditions []AlertCondition `json:"conditions"`
+	// Active indicates whether the alert is currently being watched for.
+	Active bool `json:"active"`
+	// LocationIDs are the IDs of the saved locations linked to the alert.
+	LocationIDs []LocationID `json:"location_ids,omitempty"`
+	// CreatedAt and UpdatedAt are when the alert was created and last
+	// updated, if the API returned them.
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// CreateAlertArgs are the arguments for creating an alert.
+type CreateAlertArgs struct {
+	// Name is the name of the alert.
+	Name string `json:"name"`
+	// Conditions are the thresholds that trigger the alert. At least one
+	// condition is required.
+	Conditions []AlertCondition `json:"conditions"`
+	// Active indicates whether the alert starts out active.
+	Active bool `json:"active"`
+}
+
+// ListAlertsArgs are the pagination arguments for listing alerts.
+type ListAlertsArgs struct {
+	// Page, if nonzero, is the page of alerts to retrieve, starting at 1,
+	// filling in the "page" query parameter.
+	Page int
+	// PerPage, if nonzero, is the number of alerts per page, filling in
+	// the "per_page" query parameter.
+	PerPage int
+}
+
+// QueryParams converts a ListAlertsArgs to query parameters.
+func (args ListAlertsArgs) QueryParams() url.Values {
+	return pageQueryParams(args.Page, args.PerPage)
+}
+
+// alertLocationsBody is the request body for linking locations to an alert,
+// or unlinking them.
+type alertLocationsBody struct {
+	LocationIDs []LocationID `json:"location_ids"`
+}
+
+// CreateAlert creates an alert on successful requests to the /alerts
+// endpoint, returning the created Alert, or an ErrorResponse on a 400, 401,
+// 403, or 500 error.
+func (c *Client) CreateAlert(ctx context.Context, args CreateAlertArgs) (Alert, error) {
+	if len(args.Conditions) == 0 {
+		return Alert{}, errors.New("at least one condition is required to create an alert")
+	}
+
+	var a Alert
+	err := c.call(ctx, &apiRequest{
+		method: http.MethodPost,
+		endpt:  EndpointAlerts,
+		path:   string(EndpointAlerts),
+		body:   args,
+	}, &a)
+	if err != nil {
+		return Alert{}, err
+	}
+	return a, nil
+}
+
+// ListAlerts returns a page of the account's alerts on successful requests to
+// the /alerts endpoint, or an ErrorResponse on a 400, 401, 403, or 500 error.
+// If fewer alerts than the page size are returned, that is the last page.
+func (c *Client) ListAlerts(ctx context.Context, args ListAlertsArgs) ([]Alert, error) {
+	var a []Alert
+	err := c.call(ctx, &apiRequest{
+		method: http.MethodGet,
+		endpt:  EndpointAlerts,
+		path:   string(EndpointAlerts),
+		query:  args.QueryParams(),
+	}, &a)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// GetAlert returns an alert on successful requests to the /alerts/{id}
+// endpoint, or an ErrorResponse on a 400, 401, 403, 404, or 500 error.
+func (c *Client) GetAlert(ctx context.Context, id string) (Alert, error) {
+	path, err := alertPath(id)
+	if err != nil {
+		return Alert{}, err
+	}
+
+	var a Alert
+	err = c.call(ctx, &apiRequest{
+		method: http.MethodGet,
+		endpt:  EndpointAlerts,
+		path:   path,
+	}, &a)
+	if err != nil {
+		return Alert{}, err
+	}
+	return a, nil
+}
+
+// LinkAlertLocations links locations to an alert on successful requests to
+// the /alerts/{id}/locations/link endpoint, so the alert's conditions are
+// watched for at those locations, or returns an ErrorResponse on a 400, 401,
+// 403, 404, or 500 error.
+//
+// Only saved locations can be linked to alerts, so each Location must be a
+// LocationID, or another Location whose query parameters have a location_id.
+func (c *Client) LinkAlertLocations(ctx context.Context, id string, locs ...Location) error {
+	return c.setAlertLocations(ctx, id, "link", locs)
+}
+
+// UnlinkAlertLocations unlinks locations from an alert on successful requests
+// to the /alerts/{id}/locations/unlink endpoint, or returns an ErrorResponse
+// on a 400, 401, 403, 404, or 500 error. Like with LinkAlertLocations, each
+// Location must be a saved location.
+func (c *Client) UnlinkAlertLocations(ctx context.Context, id string, locs ...Location) error {
+	return c.setAlertLocations(ctx, id, "unlink", locs)
+}
+
+func (c *Client) setAlertLocations(ctx context.Context, id, action string, locs []Location) error {
+	path, err := alertPath(id)
+	if err != nil {
+		return err
+	}
+	if len(locs) == 0 {
+		return errors.Errorf("at least one location is required to %s alert locations", action)
+	}
+
+	body := alertLocationsBody{LocationIDs: make([]LocationID, 0, len(locs))}
+	for _, loc := range locs {
+		if loc == nil {
+			return errors.New("cannot link a nil location to an alert")
+		}
+		locID := loc.LocationQueryParams().Get("location_id")
+		if locID == "" {
+			return errors.Errorf("only saved locations can be linked to alerts; got %T", loc)
+		}
+		body.LocationIDs = append(body.LocationIDs, LocationID(locID))
+	}
+
+	return c.call(ctx, &apiRequest{
+		method: http.MethodPost,
+		endpt:  EndpointAlerts,
+		path:   path + "/locations/" + action,
+		body:   body,
+	}, nil)
+}
+
+// ActivateAlert activates an alert on successful requests to the
+// /alerts/{id}/activate endpoint, or returns an ErrorResponse on a 400, 401,
+// 403, 404, or 500 error.
+func (c *Client) ActivateAlert(ctx context.Context, id string) error {
+	path, err := alertPath(id)
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, &apiRequest{
+		method: http.MethodPost,
+		endpt:  EndpointAlerts,
+		path:   path + "/activate",
+	}, nil)
+}
+
+// DeactivateAlert deactivates an alert on successful requests to the
+// /alerts/{id}/deactivate endpoint, or returns an ErrorResponse on a 400,
+// 401, 403, 404, or 500 error.
+func (c *Client) DeactivateAlert(ctx context.Context, id string) error {
+	path, err := alertPath(id)
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, &apiRequest{
+		method: http.MethodPost,
+		endpt:  EndpointAlerts,
+		path:   path + "/deactivate",
+	}, nil)
+}
+
+// DeleteAlert deletes an alert on successful requests to the /alerts/{id}
+// endpoint, or returns an ErrorResponse on a 400, 401, 403, 404, or 500
+// error.
+func (c *Client) DeleteAlert(ctx context.Context, id string) error {
+	path, err := alertPath(id)
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, &apiRequest{
+		method: http.MethodDelete,
+		endpt:  EndpointAlerts,
+		path:   path,
+	}, nil)
+}
+
+func alertPath(id string) (string, error) {
+	return resourcePath(EndpointAlerts, id)
+}
@@ -0,0 +1,117 @@
+package climacell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlerts(t *testing.T) {
+	type call struct {
+		method, path string
+		body         map[string]interface{}
+	}
+	var calls []call
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := call{method: r.Method, path: r.URL.Path}
+		if r.ContentLength > 0 {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&c.body))
+		}
+		calls = append(calls, c)
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/alerts":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{
+			  "id": "alert-1",
+			  "name": "High winds",
+			  "conditions": [{"field": "wind_gust", "operator": ">", "value": 20, "units": "m/s"}],
+			  "active": true
+			}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/alerts":
+			fmt.Fprint(w, `[{"id": "alert-1", "name": "High winds", "active": false}]`)
+		case r.URL.Path == "/alerts/missing":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"statusCode": 404, "errorCode": "NotFound", "message": "alert not found"}`)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test_api_key", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	a, err := client.CreateAlert(ctx, CreateAlertArgs{
+		Name: "High winds",
+		Conditions: []AlertCondition{
+			{Field: "wind_gust", Operator: OperatorGreaterThan, Value: 20, Units: "m/s"},
+		},
+		Active: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "alert-1", a.ID)
+	require.Len(t, a.Conditions, 1)
+	assert.Equal(t, "wind_gust > 20 m/s", a.Conditions[0].String())
+
+	_, err = client.CreateAlert(ctx, CreateAlertArgs{Name: "No conditions"})
+	assert.Error(t, err)
+
+	alerts, err := client.ListAlerts(ctx, ListAlertsArgs{Page: 1, PerPage: 10})
+	require.NoError(t, err)
+	assert.Len(t, alerts, 1)
+
+	require.NoError(t, client.LinkAlertLocations(ctx, a.ID, LocationID("loc-1"), LocationID("loc-2")))
+	assert.Error(t, client.LinkAlertLocations(ctx, a.ID, LatLon{Lat: 1, Lon: 2}))
+	require.NoError(t, client.UnlinkAlertLocations(ctx, a.ID, LocationID("loc-2")))
+	require.NoError(t, client.DeactivateAlert(ctx, a.ID))
+	require.NoError(t, client.ActivateAlert(ctx, a.ID))
+	require.NoError(t, client.DeleteAlert(ctx, a.ID))
+
+	err = client.DeleteAlert(ctx, "missing")
+	if errRes, ok := err.(*ErrorResponse); assert.True(t, ok, "expected an ErrorResponse, got %v", err) {
+		assert.Equal(t, "NotFound", errRes.ErrorCode)
+	}
+
+	require.Len(t, calls, 8)
+	assert.Equal(t, call{
+		method: http.MethodPost,
+		path:   "/alerts/alert-1/locations/link",
+		body:   map[string]interface{}{"location_ids": []interface{}{"loc-1", "loc-2"}},
+	}, calls[2])
+	assert.Equal(t, "/alerts/alert-1/locations/unlink", calls[3].path)
+	assert.Equal(t, "/alerts/alert-1/deactivate", calls[4].path)
+	assert.Equal(t, "/alerts/alert-1/activate", calls[5].path)
+	assert.Equal(t, call{method: http.MethodDelete, path: "/alerts/alert-1"}, calls[6])
+}
+
+func TestAlertIDsEscaped(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient("test_api_key", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	_, err := client.GetAlert(ctx, "")
+	assert.Error(t, err)
+	assert.Error(t, client.DeleteAlert(ctx, ""))
+	assert.Error(t, client.ActivateAlert(ctx, ".."))
+	assert.Error(t, client.DeactivateAlert(ctx, "."))
+	assert.Error(t, client.LinkAlertLocations(ctx, "", LocationID("loc-1")))
+	assert.Error(t, client.UnlinkAlertLocations(ctx, "alert-1"))
+	assert.Empty(t, paths, "requests with invalid IDs or no locations shouldn't be sent")
+
+	require.NoError(t, client.ActivateAlert(ctx, "../locations"))
+	assert.Equal(t, []string{"/alerts/..%2Flocations/activate"}, paths)
+}
@@ -0,0 +1,229 @@
+package climacell
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultBatchConcurrency is how many requests a batch sends at a time when
+// BatchArgs.Concurrency is not set.
+const DefaultBatchConcurrency = 8
+
+// BatchArgs are the arguments for requesting weather data from one endpoint
+// for many locations with Client.Batch. Either Args, or Template and
+// Locations, should be set.
+type BatchArgs struct {
+	// Endpoint is the weather data endpoint to send each request to, such
+	// as EndpointHourlyForecast.
+	Endpoint Endpoint
+	// Args are the arguments for each request.
+	Args []ForecastArgs
+	// Template, if Args is empty, is the arguments for each request, with
+	// its Location replaced by each of the Locations.
+	Template  ForecastArgs
+	Locations []Location
+	// Concurrency is the maximum number of requests to send at a time. The
+	// default is DefaultBatchConcurrency.
+	Concurrency int
+}
+
+func (args BatchArgs) forecastArgs() ([]ForecastArgs, error) {
+	if len(args.Args) > 0 {
+		if len(args.Locations) > 0 {
+			return nil, errors.New("only one of Args and Locations can be set")
+		}
+		return args.Args, nil
+	}
+
+	forecastArgs := make([]ForecastArgs, len(args.Locations))
+	for i, loc := range args.Locations {
+		forecastArgs[i] = args.Template
+		forecastArgs[i].Location = loc
+	}
+	return forecastArgs, nil
+}
+
+// BatchResult is the result of one request in a batch.
+type BatchResult struct {
+	// Args are the arguments the request was sent with.
+	Args ForecastArgs
+	// Samples, if the request succeeded, is the weather data, with the
+	// same type the Client's method for the endpoint returns. For example,
+	// Samples is a []HourlyForecast for EndpointHourlyForecast, and a
+	// RealTime for EndpointRealTime.
+	Samples interface{}
+	// Err is the error from the request, if it failed.
+	Err error
+}
+
+// BatchResults are the results of each request in a batch, keyed by the
+// LocationKey of each request's Location.
+type BatchResults map[string]BatchResult
+
+// Get returns the result of the request in the batch for a Location, and
+// whether there was a request for it.
+func (r BatchResults) Get(loc Location) (BatchResult, bool) {
+	res, ok := r[LocationKey(loc)]
+	return res, ok
+}
+
+// LocationKey returns the key a Location's result has in BatchResults, which
+// is its encoded query parameters, such as "lat=42.3&lon=-71.1".
+func LocationKey(loc Location) string {
+	if loc == nil {
+		return ""
+	}
+	return loc.LocationQueryParams().Encode()
+}
+
+// BatchError is the error returned from Client.Batch when any requests in
+// the batch failed. Each failed request's error is also on its BatchResult.
+type BatchError struct {
+	// Errors are the errors from each failed request, keyed by the
+	// LocationKey of the request's Location.
+	Errors map[string]error
+	// Total is the number of requests in the batch.
+	Total int
+}
+
+func (err *BatchError) Error() string {
+	keys := make([]string, 0, len(err.Errors))
+	for k := range err.Errors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	msgs := make([]string, len(keys))
+	for i, k := range keys {
+		msgs[i] = fmt.Sprintf("%s: %v", k, err.Errors[k])
+	}
+	return fmt.Sprintf("%d of %d batch requests failed; %s",
+		len(err.Errors), err.Total, strings.Join(msgs, "; "))
+}
+
+// Batch requests weather data from one endpoint for many locations, sending
+// up to args.Concurrency requests at a time. Each request goes through the
+// Client like any other, so requests share the Client's rate limiter, retry
+// policy, cache, and middleware.
+//
+// The results of every request are returned, even if some requests failed,
+// in which case the error is a *BatchError. Each request must be for a
+// different location, since results are keyed by location. If ctx is done
+// before every request is sent, the unsent requests fail with ctx's error.
+func (c *Client) Batch(ctx context.Context, args BatchArgs) (BatchResults, error) {
+	forecastArgs, err := args.forecastArgs()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := newSamples(args.Endpoint); err != nil {
+		return nil, err
+	}
+	for i, a := range forecastArgs {
+		if a.Location == nil {
+			return nil, errors.Errorf("request %d in the batch has no location", i)
+		}
+	}
+
+	results := make(BatchResults, len(forecastArgs))
+	for _, a := range forecastArgs {
+		key := LocationKey(a.Location)
+		if _, ok := results[key]; ok {
+			return nil, errors.Errorf("location %s is in the batch more than once", key)
+		}
+		results[key] = BatchResult{Args: a}
+	}
+
+	concurrency := args.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, a := range forecastArgs {
+		var acquired bool
+		select {
+		case sem <- struct{}{}:
+			acquired = true
+		case <-ctx.Done():
+		}
+
+		wg.Add(1)
+		go func(a ForecastArgs, acquired bool) {
+			defer wg.Done()
+			res := BatchResult{Args: a}
+			if acquired {
+				defer func() { <-sem }()
+				res.Samples, res.Err = c.batchRequest(ctx, args.Endpoint, a)
+			} else {
+				res.Err = errors.WithMessage(ctx.Err(), "batch cancelled before request was sent")
+			}
+
+			mu.Lock()
+			results[LocationKey(a.Location)] = res
+			mu.Unlock()
+		}(a, acquired)
+	}
+	wg.Wait()
+
+	batchErr := &BatchError{Errors: make(map[string]error), Total: len(results)}
+	for key, res := range results {
+		if res.Err != nil {
+			batchErr.Errors[key] = res.Err
+		}
+	}
+	if len(batchErr.Errors) > 0 {
+		return results, batchErr
+	}
+	return results, nil
+}
+
+func (c *Client) batchRequest(ctx context.Context, endpt Endpoint, args ForecastArgs) (interface{}, error) {
+	samples, _ := newSamples(endpt)
+	if err := c.getWeatherSamples(ctx, endpt, args, samples); err != nil {
+		return nil, err
+	}
+	switch s := samples.(type) {
+	case *[]NowCastForecast:
+		return *s, nil
+	case *[]HourlyForecast:
+		return *s, nil
+	case *[]ForecastDay:
+		return *s, nil
+	case *[]HistoricalStation:
+		return *s, nil
+	case *[]HistoricalClimaCell:
+		return *s, nil
+	default:
+		return *samples.(*RealTime), nil
+	}
+}
+
+// newSamples returns a pointer to deserialize an endpoint's weather data
+// into.
+func newSamples(endpt Endpoint) (interface{}, error) {
+	switch endpt {
+	case EndpointNowcast:
+		return &[]NowCastForecast{}, nil
+	case EndpointHourlyForecast:
+		return &[]HourlyForecast{}, nil
+	case EndpointDailyForecast:
+		return &[]ForecastDay{}, nil
+	case EndpointHistoricalStation:
+		return &[]HistoricalStation{}, nil
+	case EndpointHistoricalClimaCell:
+		return &[]HistoricalClimaCell{}, nil
+	case EndpointRealTime:
+		return &RealTime{}, nil
+	default:
+		return nil, errors.Errorf("batches are not supported for endpoint %q", endpt)
+	}
+}
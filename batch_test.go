@@ -0,0 +1,143 @@
+package climacell
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchServer responds to hourly forecast requests with a sample at the
+// requested location, or a 400 for latitudes over 90. It records the most
+// requests it handled at once.
+func batchServer(maxInFlight *int32) *httptest.Server {
+	var inFlight int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		q := r.URL.Query()
+		if q.Get("lat") == "95" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode": 400, "errorCode": "BadRequest", "message": "lat out of range"}`))
+			return
+		}
+		w.Write([]byte(`[{"lat": ` + q.Get("lat") + `, "lon": ` + q.Get("lon") + `}]`))
+	}))
+}
+
+func TestBatchTemplate(t *testing.T) {
+	var maxInFlight int32
+	srv := batchServer(&maxInFlight)
+	defer srv.Close()
+
+	locs := make([]Location, 10)
+	for i := range locs {
+		locs[i] = LatLon{Lat: float64(i), Lon: -71}
+	}
+	locs[3] = LatLon{Lat: 95, Lon: -71}
+
+	c := NewClient("test_api_key", WithBaseURL(srv.URL))
+	results, err := c.Batch(context.Background(), BatchArgs{
+		Endpoint:    EndpointHourlyForecast,
+		Template:    ForecastArgs{Fields: []string{"temp"}},
+		Locations:   locs,
+		Concurrency: 3,
+	})
+	require.Len(t, results, 10)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(3))
+
+	var batchErr *BatchError
+	require.True(t, stderrors.As(err, &batchErr))
+	assert.Equal(t, 10, batchErr.Total)
+	assert.Len(t, batchErr.Errors, 1)
+	assert.Contains(t, err.Error(), "1 of 10 batch requests failed")
+
+	failed, ok := results.Get(LatLon{Lat: 95, Lon: -71})
+	require.True(t, ok)
+	assert.IsType(t, &ErrorResponse{}, failed.Err)
+	assert.Nil(t, failed.Samples)
+
+	// the other results are available even though one request failed
+	res, ok := results.Get(LatLon{Lat: 4, Lon: -71})
+	require.True(t, ok)
+	require.NoError(t, res.Err)
+	assert.Equal(t, []string{"temp"}, res.Args.Fields)
+	if samples, ok := res.Samples.([]HourlyForecast); assert.True(t, ok) {
+		require.Len(t, samples, 1)
+		assert.Equal(t, LatLon{Lat: 4, Lon: -71}, samples[0].LatLon)
+	}
+}
+
+func TestBatchArgs(t *testing.T) {
+	var maxInFlight int32
+	srv := batchServer(&maxInFlight)
+	defer srv.Close()
+
+	c := NewClient("test_api_key", WithBaseURL(srv.URL))
+	results, err := c.Batch(context.Background(), BatchArgs{
+		Endpoint: EndpointHourlyForecast,
+		Args: []ForecastArgs{
+			{Location: LatLon{Lat: 1, Lon: 2}},
+			{Location: LatLon{Lat: 3, Lon: 4}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	_, ok := results.Get(LatLon{Lat: 3, Lon: 4})
+	assert.True(t, ok)
+}
+
+func TestBatchErrors(t *testing.T) {
+	c := NewClient("test_api_key")
+	ctx := context.Background()
+
+	_, err := c.Batch(ctx, BatchArgs{
+		Endpoint:  EndpointLocations,
+		Locations: []Location{LatLon{}},
+	})
+	assert.Error(t, err, "unsupported endpoint")
+
+	_, err = c.Batch(ctx, BatchArgs{
+		Endpoint:  EndpointHourlyForecast,
+		Locations: []Location{LatLon{Lat: 1}, LatLon{Lat: 1}},
+	})
+	assert.Error(t, err, "duplicate location")
+
+	_, err = c.Batch(ctx, BatchArgs{
+		Endpoint:  EndpointHourlyForecast,
+		Args:      []ForecastArgs{{Location: LatLon{}}},
+		Locations: []Location{LatLon{}},
+	})
+	assert.Error(t, err, "both Args and Locations")
+}
+
+func TestBatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := NewClient("test_api_key")
+	results, err := c.Batch(ctx, BatchArgs{
+		Endpoint:  EndpointRealTime,
+		Locations: []Location{LatLon{Lat: 1}, LatLon{Lat: 2}},
+	})
+	assert.Error(t, err)
+	require.Len(t, results, 2)
+	for _, res := range results {
+		assert.Equal(t, context.Canceled, errors.Cause(res.Err))
+	}
+}
@@ -0,0 +1,228 @@
+package climacell
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Cache stores API responses so that repeated requests for the same weather
+// data don't use up quota. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored for a key, and whether a value that
+	// has not yet expired was found.
+	Get(key string) ([]byte, bool)
+	// Set stores a value for a key, which expires after the given TTL.
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// DefaultCacheTTLs are how long responses from each endpoint are cached for
+// by default when a Client has a Cache. Endpoints whose data changes often,
+// like nowcasts, are cached for a short time, while daily forecasts are
+// cached for longer.
+var DefaultCacheTTLs = map[Endpoint]time.Duration{
+	EndpointNowcast:             time.Minute,
+	EndpointRealTime:            time.Minute,
+	EndpointHourlyForecast:      10 * time.Minute,
+	EndpointHistoricalClimaCell: 10 * time.Minute,
+	EndpointDailyForecast:       time.Hour,
+	EndpointHistoricalStation:   time.Hour,
+}
+
+// WithCache sets the Cache the Client stores responses in, using the TTLs in
+// DefaultCacheTTLs unless overridden with WithCacheTTL. By default, responses
+// are not cached.
+func WithCache(cache Cache) Option {
+	return func(c *Client) { c.cache = cache }
+}
+
+// WithCacheTTL sets how long responses from an endpoint are cached for. A TTL
+// of zero turns off caching for that endpoint.
+func WithCacheTTL(endpt Endpoint, ttl time.Duration) Option {
+	return func(c *Client) {
+		if c.cacheTTLs == nil {
+			c.cacheTTLs = make(map[Endpoint]time.Duration)
+		}
+		c.cacheTTLs[endpt] = ttl
+	}
+}
+
+// CacheStats contains the number of cache hits and misses for a Client's
+// requests to cached endpoints.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// CacheStats returns the number of cache hits and misses for the Client's
+// requests since it was created.
+func (c *Client) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadUint64(&c.cacheHits),
+		Misses: atomic.LoadUint64(&c.cacheMisses),
+	}
+}
+
+// cacheTTL returns how long to cache responses from the given endpoint, or 0
+// if they are not to be cached.
+func (c *Client) cacheTTL(endpt Endpoint) time.Duration {
+	if c.cache == nil {
+		return 0
+	}
+	if ttl, ok := c.cacheTTLs[endpt]; ok {
+		return ttl
+	}
+	return DefaultCacheTTLs[endpt]
+}
+
+// cacheKey returns the key a response is cached under, which is made from
+// the URL the request for it is sent to.
+func (c *Client) cacheKey(endpt Endpoint, args ForecastArgs) string {
+	return c.baseURL + string(endpt) + "?" + args.QueryParams().Encode()
+}
+
+// LRUCache is an in-memory Cache that holds up to a fixed number of entries,
+// evicting the least recently used entry when it is full.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	entries  map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache returns an LRUCache holding up to capacity entries. If capacity
+// is not positive, the cache holds any number of entries.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		ll:       list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get implements the Cache interface.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*lruEntry)
+	if time.Now().After(e.expires) {
+		c.ll.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// Set implements the Cache interface.
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := time.Now().Add(ttl)
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*lruEntry)
+		e.value, e.expires = value, expires
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.ll.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	if c.capacity > 0 && c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of entries in the cache, including expired entries
+// that have not been evicted yet.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// FileCache is a Cache that stores each entry as a file in a directory, so
+// cached responses can be shared between processes, and kept across
+// restarts. Errors reading or writing files are treated as cache misses.
+type FileCache struct{ dir string }
+
+// NewFileCache returns a FileCache that stores entries in the given
+// directory, creating the directory if it doesn't exist.
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.WithMessage(err, "creating cache directory")
+	}
+	return &FileCache{dir: dir}, nil
+}
+
+// Each file starts with the entry's expiration time as a big-endian Unix
+// timestamp in nanoseconds, followed by the entry's value.
+const fileCacheHeaderLen = 8
+
+// Get implements the Cache interface.
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	path := c.path(key)
+	b, err := ioutil.ReadFile(path)
+	if err != nil || len(b) < fileCacheHeaderLen {
+		return nil, false
+	}
+
+	expires := time.Unix(0, int64(binary.BigEndian.Uint64(b)))
+	if time.Now().After(expires) {
+		os.Remove(path)
+		return nil, false
+	}
+	return b[fileCacheHeaderLen:], true
+}
+
+// Set implements the Cache interface.
+func (c *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	b := make([]byte, fileCacheHeaderLen+len(value))
+	binary.BigEndian.PutUint64(b, uint64(time.Now().Add(ttl).UnixNano()))
+	copy(b[fileCacheHeaderLen:], value)
+
+	// Write to a temporary file and then rename it so that concurrent
+	// readers never see a partially-written entry.
+	f, err := ioutil.TempFile(c.dir, ".tmp-")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return
+	}
+	if err := os.Rename(f.Name(), c.path(key)); err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,95 @@
+package climacell
+
+import (
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCacheEviction(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("b", []byte("2"), time.Minute)
+
+	// reading a makes b the least recently used entry
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+	c.Set("c", []byte("3"), time.Minute)
+
+	_, ok = c.Get("b")
+	assert.False(t, ok, "b should have been evicted")
+	if v, ok := c.Get("a"); assert.True(t, ok) {
+		assert.Equal(t, "1", string(v))
+	}
+	if v, ok := c.Get("c"); assert.True(t, ok) {
+		assert.Equal(t, "3", string(v))
+	}
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	c := NewLRUCache(0)
+	c.Set("a", []byte("1"), -time.Second)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestFileCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "climacell-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c, err := NewFileCache(dir)
+	require.NoError(t, err)
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Set("a", []byte(`[{"temp": 1}]`), time.Minute)
+	if v, ok := c.Get("a"); assert.True(t, ok) {
+		assert.Equal(t, `[{"temp": 1}]`, string(v))
+	}
+
+	c.Set("b", []byte("2"), -time.Second)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+}
+
+func TestClientCachesResponses(t *testing.T) {
+	server, calls := flakyServer(0, 0, nil)
+	defer server.Close()
+
+	client := NewClient("test_api_key",
+		WithBaseURL(server.URL),
+		WithCache(NewLRUCache(10)),
+		WithCacheTTL(EndpointNowcast, 0),
+	)
+
+	args := ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}}
+	for i := 0; i < 3; i++ {
+		_, err := client.HourlyForecast(args)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1}, client.CacheStats())
+
+	// different query parameters are cached separately
+	args.Fields = []string{"temp"}
+	_, err := client.HourlyForecast(args)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(calls))
+
+	// nowcasts have caching turned off
+	for i := 0; i < 2; i++ {
+		_, err := client.Nowcast(args)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 4, atomic.LoadInt32(calls))
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 2}, client.CacheStats())
+}
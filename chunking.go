@@ -0,0 +1,248 @@
+package climacell
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxChunkWindows are the longest time ranges the historical endpoints allow
+// in a single request.
+var maxChunkWindows = map[Endpoint]time.Duration{
+	EndpointHistoricalClimaCell: 6 * time.Hour,
+	EndpointHistoricalStation:   24 * time.Hour,
+}
+
+// maxSamplesPerRequest is the most weather samples the API returns from a
+// single request, which limits the time range of requests with a small
+// Timestep.
+const maxSamplesPerRequest = 1000
+
+// ChunkOptions configure how a long historical query is split into chunks.
+type ChunkOptions struct {
+	// Window, if nonzero, is the time range of each chunk. The default,
+	// which is also the maximum, is the longest time range the endpoint
+	// allows in a request with the ForecastArgs' Timestep.
+	Window time.Duration
+	// Concurrency is the maximum number of chunks to fetch at a time. The
+	// default, 1, fetches the chunks in order, one at a time. Either way,
+	// chunks are returned in order.
+	Concurrency int
+}
+
+// ChunkArgs splits the time range of a ForecastArgs for a historical endpoint
+// into consecutive ForecastArgs whose time ranges the endpoint allows in a
+// single request. Consecutive chunks share their boundary, so the sample at
+// the boundary can be in the results of both chunks.
+func ChunkArgs(endpt Endpoint, args ForecastArgs, window time.Duration) ([]ForecastArgs, error) {
+	maxWindow, ok := maxChunkWindows[endpt]
+	if !ok {
+		return nil, errors.Errorf("chunking is not supported for endpoint %q", endpt)
+	}
+	if args.Start.IsZero() || args.End.IsZero() {
+		return nil, errors.New("both Start and End are required to chunk a time range")
+	}
+	if !args.End.After(args.Start) {
+		return nil, errors.New("End must be after Start")
+	}
+	if args.Timestep > 0 {
+		if byTimestep := time.Duration(args.Timestep) * time.Minute * maxSamplesPerRequest; byTimestep < maxWindow {
+			maxWindow = byTimestep
+		}
+	}
+	if window <= 0 || window > maxWindow {
+		window = maxWindow
+	}
+
+	var chunks []ForecastArgs
+	for start := args.Start; start.Before(args.End); start = start.Add(window) {
+		chunk := args
+		chunk.Start = start
+		if end := start.Add(window); end.Before(args.End) {
+			chunk.End = end
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// ChunkIterator iterates over the results of each chunk of a long historical
+// query, in order. Call Next to advance to each chunk, Samples to get its
+// weather data, and Err after Next returns false to check whether every chunk
+// was fetched successfully. If you stop iterating before Next returns false,
+// call Close to stop fetching chunks.
+type ChunkIterator struct {
+	chunks  []ForecastArgs
+	results []chan chunkResult
+	cancel  context.CancelFunc
+
+	i   int
+	cur chunkResult
+	err error
+}
+
+type chunkResult struct {
+	samples interface{}
+	err     error
+}
+
+// HistoricalChunks returns a ChunkIterator for requesting the weather data for
+// args' full time range from a historical endpoint, split into chunks with
+// ChunkArgs. Each chunk's Samples are a []HistoricalClimaCell for
+// EndpointHistoricalClimaCell, or a []HistoricalStation for
+// EndpointHistoricalStation.
+func (c *Client) HistoricalChunks(
+	ctx context.Context,
+	endpt Endpoint,
+	args ForecastArgs,
+	opts ChunkOptions,
+) (*ChunkIterator, error) {
+	chunks, err := ChunkArgs(endpt, args, opts.Window)
+	if err != nil {
+		return nil, err
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	it := &ChunkIterator{
+		chunks:  chunks,
+		results: make([]chan chunkResult, len(chunks)),
+		cancel:  cancel,
+		i:       -1,
+	}
+	for i := range it.results {
+		it.results[i] = make(chan chunkResult, 1)
+	}
+
+	go func() {
+		sem := make(chan struct{}, concurrency)
+		for i, chunk := range chunks {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				it.results[i] <- chunkResult{err: ctx.Err()}
+				continue
+			}
+			go func(i int, chunk ForecastArgs) {
+				defer func() { <-sem }()
+				samples, err := c.batchRequest(ctx, endpt, chunk)
+				it.results[i] <- chunkResult{samples: samples, err: err}
+			}(i, chunk)
+		}
+	}()
+	return it, nil
+}
+
+// Next advances the iterator to the next chunk, returning false when there
+// are no more chunks, or when fetching a chunk failed.
+func (it *ChunkIterator) Next() bool {
+	if it.err != nil || it.i+1 >= len(it.chunks) {
+		it.Close()
+		return false
+	}
+	it.i++
+	it.cur = <-it.results[it.i]
+	if it.cur.err != nil {
+		chunk := it.chunks[it.i]
+		it.err = errors.WithMessagef(it.cur.err, "fetching chunk from %s to %s",
+			chunk.Start.Format(time.RFC3339), chunk.End.Format(time.RFC3339))
+		it.Close()
+		return false
+	}
+	return true
+}
+
+// Args returns the ForecastArgs of the current chunk.
+func (it *ChunkIterator) Args() ForecastArgs { return it.chunks[it.i] }
+
+// Samples returns the weather data of the current chunk.
+func (it *ChunkIterator) Samples() interface{} { return it.cur.samples }
+
+// Err returns the error from fetching a chunk, if any.
+func (it *ChunkIterator) Err() error { return it.err }
+
+// Len returns the number of chunks the query was split into.
+func (it *ChunkIterator) Len() int { return len(it.chunks) }
+
+// Close stops fetching chunks. It is safe to call more than once.
+func (it *ChunkIterator) Close() { it.cancel() }
+
+// HistoricalClimaCellRange returns the weather data for args' full time range
+// from the /weather/historical/climacell endpoint, fetching it in chunks with
+// HistoricalChunks. The samples are sorted by ObservationTime, without the
+// duplicate samples at the boundaries of chunks.
+func (c *Client) HistoricalClimaCellRange(
+	ctx context.Context,
+	args ForecastArgs,
+	opts ChunkOptions,
+) ([]HistoricalClimaCell, error) {
+	it, err := c.HistoricalChunks(ctx, EndpointHistoricalClimaCell, args, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var merged []HistoricalClimaCell
+	seen := make(map[string]bool)
+	for it.Next() {
+		for _, s := range it.Samples().([]HistoricalClimaCell) {
+			if key := sampleKey(s.BaseResponseType); !seen[key] {
+				seen[key] = true
+				merged = append(merged, s)
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].ObservationTime.Value.Before(merged[j].ObservationTime.Value)
+	})
+	return merged, nil
+}
+
+// HistoricalStationRange is like HistoricalClimaCellRange, but for the
+// /weather/historical/station endpoint.
+func (c *Client) HistoricalStationRange(
+	ctx context.Context,
+	args ForecastArgs,
+	opts ChunkOptions,
+) ([]HistoricalStation, error) {
+	it, err := c.HistoricalChunks(ctx, EndpointHistoricalStation, args, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var merged []HistoricalStation
+	seen := make(map[string]bool)
+	for it.Next() {
+		for _, s := range it.Samples().([]HistoricalStation) {
+			if key := sampleKey(s.BaseResponseType); !seen[key] {
+				seen[key] = true
+				merged = append(merged, s)
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].ObservationTime.Value.Before(merged[j].ObservationTime.Value)
+	})
+	return merged, nil
+}
+
+// sampleKey identifies a weather sample by its location and observation time,
+// for dropping duplicate samples.
+func sampleKey(b BaseResponseType) string {
+	return fmt.Sprintf("%v,%v,%s,%d", b.Lat, b.Lon, b.LocationId, b.ObservationTime.Value.UnixNano())
+}
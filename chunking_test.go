@@ -0,0 +1,131 @@
+package climacell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// historicalServer responds with an hourly sample for every hour from the
+// requested start time to the end time, inclusive, in reverse order. Requests
+// starting at failAt get a 500 error, and requests with unparseable times get
+// a 400 error, which the test sees as the iterator's error.
+func historicalServer(failAt time.Time, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		q := r.URL.Query()
+		start, startErr := time.Parse(time.RFC3339, q.Get("start_time"))
+		end, endErr := time.Parse(time.RFC3339, q.Get("end_time"))
+		if startErr != nil || endErr != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"statusCode": 400, "message": "invalid start_time or end_time"}`))
+			return
+		}
+		if start.Equal(failAt) {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"statusCode": 500, "message": "internal error"}`))
+			return
+		}
+
+		var samples []json.RawMessage
+		for ts := end; !ts.Before(start); ts = ts.Add(-time.Hour) {
+			samples = append(samples, json.RawMessage(fmt.Sprintf(
+				`{"lat": 42.3, "lon": -71.1, "observation_time": {"value": %q}}`,
+				ts.Format(time.RFC3339),
+			)))
+		}
+		json.NewEncoder(w).Encode(samples)
+	}))
+}
+
+func TestChunkArgs(t *testing.T) {
+	start := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	args := ForecastArgs{Start: start, End: start.Add(14 * time.Hour)}
+
+	chunks, err := ChunkArgs(EndpointHistoricalClimaCell, args, 0)
+	require.NoError(t, err)
+	require.Len(t, chunks, 3)
+	assert.Equal(t, start.Add(6*time.Hour), chunks[0].End)
+	assert.Equal(t, start.Add(6*time.Hour), chunks[1].Start)
+	assert.Equal(t, start.Add(14*time.Hour), chunks[2].End)
+
+	// windows longer than the endpoint allows are shortened
+	chunks, err = ChunkArgs(EndpointHistoricalClimaCell, args, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, chunks, 3)
+
+	chunks, err = ChunkArgs(EndpointHistoricalStation, args, 0)
+	require.NoError(t, err)
+	assert.Len(t, chunks, 1)
+
+	_, err = ChunkArgs(EndpointHourlyForecast, args, 0)
+	assert.Error(t, err)
+	_, err = ChunkArgs(EndpointHistoricalStation, ForecastArgs{Start: start}, 0)
+	assert.Error(t, err)
+}
+
+func TestHistoricalClimaCellRange(t *testing.T) {
+	var calls int32
+	srv := historicalServer(time.Time{}, &calls)
+	defer srv.Close()
+
+	c := NewClient("test_api_key", WithBaseURL(srv.URL))
+	start := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	for _, concurrency := range []int{1, 3} {
+		atomic.StoreInt32(&calls, 0)
+		samples, err := c.HistoricalClimaCellRange(context.Background(), ForecastArgs{
+			Location: LatLon{Lat: 42.3, Lon: -71.1},
+			Start:    start,
+			End:      start.Add(14 * time.Hour),
+		}, ChunkOptions{Concurrency: concurrency})
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+
+		// samples at chunk boundaries are only included once
+		require.Len(t, samples, 15)
+		for i, s := range samples {
+			assert.Equal(t, start.Add(time.Duration(i)*time.Hour), s.ObservationTime.Value)
+		}
+	}
+}
+
+func TestHistoricalChunksError(t *testing.T) {
+	var calls int32
+	start := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	srv := historicalServer(start.Add(24*time.Hour), &calls)
+	defer srv.Close()
+
+	c := NewClient("test_api_key", WithBaseURL(srv.URL))
+	it, err := c.HistoricalChunks(context.Background(), EndpointHistoricalStation, ForecastArgs{
+		Location: LatLon{Lat: 42.3, Lon: -71.1},
+		Start:    start,
+		End:      start.Add(72 * time.Hour),
+	}, ChunkOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, it.Len())
+
+	require.True(t, it.Next())
+	assert.Equal(t, start, it.Args().Start)
+	assert.Len(t, it.Samples().([]HistoricalStation), 25)
+
+	assert.False(t, it.Next())
+	assert.False(t, it.Next())
+	require.Error(t, it.Err())
+	assert.IsType(t, &ErrorResponse{}, errors.Cause(it.Err()))
+
+	_, err = c.HistoricalStationRange(context.Background(), ForecastArgs{
+		Location: LatLon{Lat: 42.3, Lon: -71.1},
+		Start:    start,
+		End:      start.Add(72 * time.Hour),
+	}, ChunkOptions{Concurrency: 3})
+	assert.Error(t, err)
+}
@@ -1,10 +1,15 @@
 package climacell
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -13,18 +18,68 @@ import (
 // Client is the client for sending HTTP requests to ClimaCell's HTTP
 // endpoints.
 type Client struct {
+	// the number of cache hits and misses for requests to cached
+	// endpoints. These come first in the struct so they are 64-bit aligned
+	// for sync/atomic.
+	cacheHits, cacheMisses uint64
+
 	// the base URL to send requests to. In regular usage, this is
-	// https://api.climacell.co/v3, but in test coverage this can be set to
-	// the URL for a mock API server.
+	// https://api.climacell.co/v3, but it can be set to a different URL
+	// with WithBaseURL, such as the URL for a mock API server.
 	baseURL string
 
+	// the base URL to send requests to v4 of the API to. In regular usage,
+	// this is https://data.climacell.co/v4, but it can be set with
+	// WithV4BaseURL.
+	v4BaseURL string
+
 	// the API key we are sending requests with
 	apiKey string
 
 	// net/http Client for contacting the ClimaCell API.
 	c *http.Client
+
+	// the policy for retrying failed requests. If nil, failed requests are
+	// not retried.
+	retry *RetryPolicy
+
+	// the rate limiter requests wait on before being sent. If nil, requests
+	// are not rate limited.
+	limiter *RateLimiter
+
+	// if nonempty, the User-Agent header to send requests with
+	userAgent string
+
+	// the unit system and fields to request when a ForecastArgs doesn't
+	// specify them
+	unitSystem string
+	fields     []string
+
+	// the logger to log to. If nil, nothing is logged.
+	logger Logger
+
+	// the cache for responses, and how long to cache responses from each
+	// endpoint. If cache is nil, responses are not cached.
+	cache     Cache
+	cacheTTLs map[Endpoint]time.Duration
+
+	// the middleware requests for weather data go through, outermost first
+	middleware []Middleware
+
+	// where to record metrics on requests. If nil, no metrics are recorded.
+	metrics Metrics
+
+	// the tracer to start spans for requests with. If nil, requests are not
+	// traced.
+	tracer Tracer
 }
 
+// The default base URLs for v3 and v4 of the ClimaCell API.
+const (
+	defaultBaseURL   = "https://api.climacell.co/v3/"
+	defaultV4BaseURL = "https://data.climacell.co/v4/"
+)
+
 func newDefaultHTTPClient() *http.Client { return &http.Client{Timeout: time.Minute} }
 
 // New takes in a ClimaCell API key and returns a client for the ClimaCell API.
@@ -43,17 +98,38 @@ func New(apiKey string) *Client { return NewWithClient(apiKey, newDefaultHTTPCli
 // to it, they can make requests to the API under your identity. Because of
 // this, it is ill-advised to have the key directly in your source code.
 func NewWithClient(apiKey string, c *http.Client) *Client {
-	return &Client{
-		baseURL: "https://api.climacell.co/v3/",
-		apiKey:  apiKey,
-		c:       c,
-	}
+	return NewClient(apiKey, WithHTTPClient(c))
 }
 
+// SetRetryPolicy sets the policy the Client uses for retrying failed
+// requests. Passing in nil turns off retries, which is the default. This
+// should be called before the Client is used for sending requests.
+func (c *Client) SetRetryPolicy(p *RetryPolicy) { c.retry = p }
+
+// SetRateLimiter sets the RateLimiter the Client waits on before sending each
+// request, including retries. Passing in nil turns off rate limiting, which is
+// the default. This should be called before the Client is used for sending
+// requests.
+func (c *Client) SetRateLimiter(l *RateLimiter) { c.limiter = l }
+
 //
 // Weather endpoints
 //
 
+// Endpoint is the path to one of the ClimaCell API's endpoints, relative to
+// the base URL.
+type Endpoint string
+
+// The ClimaCell API's weather data endpoints.
+const (
+	EndpointNowcast             Endpoint = "weather/nowcast"
+	EndpointHourlyForecast      Endpoint = "weather/forecast/hourly"
+	EndpointDailyForecast       Endpoint = "weather/forecast/daily"
+	EndpointHistoricalStation   Endpoint = "weather/historical/station"
+	EndpointHistoricalClimaCell Endpoint = "weather/historical/climacell"
+	EndpointRealTime            Endpoint = "weather/realtime"
+)
+
 // Nowcast returns minute-by-minute weather predictions on successful requests
 // to the /weather/nowcast endpoint, returning a slice of Weather samples on a
 // 200 response, or an ErrorResponse on a 400, 401, 403, or 500 error. You are
@@ -65,8 +141,16 @@ func NewWithClient(apiKey string, c *http.Client) *Client {
 // things such as errors sending the request to the API, or unexpected errors
 // deserializing responses.
 func (c *Client) Nowcast(args ForecastArgs) ([]NowCastForecast, error) {
+	return c.NowcastWithContext(context.Background(), args)
+}
+
+// NowcastWithContext is like Nowcast, but sends its request with the provided
+// context, so the request can be cancelled, or given a deadline. If the context
+// is done before the request completes, the returned error's pkg/errors.Cause()
+// is the context's error.
+func (c *Client) NowcastWithContext(ctx context.Context, args ForecastArgs) ([]NowCastForecast, error) {
 	var w []NowCastForecast
-	if err := c.getWeatherSamples("weather/nowcast", args, &w); err != nil {
+	if err := c.getWeatherSamples(ctx, EndpointNowcast, args, &w); err != nil {
 		return nil, err
 	}
 	return w, nil
@@ -83,8 +167,16 @@ func (c *Client) Nowcast(args ForecastArgs) ([]NowCastForecast, error) {
 // things such as errors sending the request to the API, or unexpected errors
 // deserializing responses.
 func (c *Client) HourlyForecast(args ForecastArgs) ([]HourlyForecast, error) {
+	return c.HourlyForecastWithContext(context.Background(), args)
+}
+
+// HourlyForecastWithContext is like HourlyForecast, but sends its request with
+// the provided context, so the request can be cancelled, or given a deadline.
+// If the context is done before the request completes, the returned error's
+// pkg/errors.Cause() is the context's error.
+func (c *Client) HourlyForecastWithContext(ctx context.Context, args ForecastArgs) ([]HourlyForecast, error) {
 	var w []HourlyForecast
-	if err := c.getWeatherSamples("weather/forecast/hourly", args, &w); err != nil {
+	if err := c.getWeatherSamples(ctx, EndpointHourlyForecast, args, &w); err != nil {
 		return nil, err
 	}
 	return w, nil
@@ -101,8 +193,16 @@ func (c *Client) HourlyForecast(args ForecastArgs) ([]HourlyForecast, error) {
 // things such as errors sending the request to the API, or unexpected errors
 // deserializing responses.
 func (c *Client) DailyForecast(args ForecastArgs) ([]ForecastDay, error) {
+	return c.DailyForecastWithContext(context.Background(), args)
+}
+
+// DailyForecastWithContext is like DailyForecast, but sends its request with
+// the provided context, so the request can be cancelled, or given a deadline.
+// If the context is done before the request completes, the returned error's
+// pkg/errors.Cause() is the context's error.
+func (c *Client) DailyForecastWithContext(ctx context.Context, args ForecastArgs) ([]ForecastDay, error) {
 	var f []ForecastDay
-	if err := c.getWeatherSamples("weather/forecast/daily", args, &f); err != nil {
+	if err := c.getWeatherSamples(ctx, EndpointDailyForecast, args, &f); err != nil {
 		return nil, err
 	}
 	return f, nil
@@ -119,8 +219,16 @@ func (c *Client) DailyForecast(args ForecastArgs) ([]ForecastDay, error) {
 // things such as errors sending the request to the API, or unexpected errors
 // deserializing responses.
 func (c *Client) HistoricalStation(args ForecastArgs) ([]HistoricalStation, error) {
+	return c.HistoricalStationWithContext(context.Background(), args)
+}
+
+// HistoricalStationWithContext is like HistoricalStation, but sends its request
+// with the provided context, so the request can be cancelled, or given a
+// deadline. If the context is done before the request completes, the returned
+// error's pkg/errors.Cause() is the context's error.
+func (c *Client) HistoricalStationWithContext(ctx context.Context, args ForecastArgs) ([]HistoricalStation, error) {
 	var f []HistoricalStation
-	if err := c.getWeatherSamples("weather/historical/station", args, &f); err != nil {
+	if err := c.getWeatherSamples(ctx, EndpointHistoricalStation, args, &f); err != nil {
 		return nil, err
 	}
 	return f, nil
@@ -137,8 +245,16 @@ func (c *Client) HistoricalStation(args ForecastArgs) ([]HistoricalStation, erro
 // things such as errors sending the request to the API, or unexpected errors
 // deserializing responses.
 func (c *Client) HistoricalClimaCell(args ForecastArgs) ([]HistoricalClimaCell, error) {
+	return c.HistoricalClimaCellWithContext(context.Background(), args)
+}
+
+// HistoricalClimaCellWithContext is like HistoricalClimaCell, but sends its
+// request with the provided context, so the request can be cancelled, or given
+// a deadline. If the context is done before the request completes, the returned
+// error's pkg/errors.Cause() is the context's error.
+func (c *Client) HistoricalClimaCellWithContext(ctx context.Context, args ForecastArgs) ([]HistoricalClimaCell, error) {
 	var f []HistoricalClimaCell
-	if err := c.getWeatherSamples("weather/historical/climacell", args, &f); err != nil {
+	if err := c.getWeatherSamples(ctx, EndpointHistoricalClimaCell, args, &f); err != nil {
 		return nil, err
 	}
 	return f, nil
@@ -154,64 +270,360 @@ func (c *Client) HistoricalClimaCell(args ForecastArgs) ([]HistoricalClimaCell,
 // things such as errors sending the request to the API, or unexpected errors
 // deserializing responses.
 func (c *Client) RealTime(args ForecastArgs) (RealTime, error) {
+	return c.RealTimeWithContext(context.Background(), args)
+}
+
+// RealTimeWithContext is like RealTime, but sends its request with the provided
+// context, so the request can be cancelled, or given a deadline. If the context
+// is done before the request completes, the returned error's pkg/errors.Cause()
+// is the context's error.
+func (c *Client) RealTimeWithContext(ctx context.Context, args ForecastArgs) (RealTime, error) {
 	var f RealTime
-	if err := c.getWeatherSamples("weather/realtime", args, &f); err != nil {
+	if err := c.getWeatherSamples(ctx, EndpointRealTime, args, &f); err != nil {
 		return RealTime{}, err
 	}
 	return f, nil
 }
 
 func (c *Client) getWeatherSamples(
-	endpt string,
+	ctx context.Context,
+	endpt Endpoint,
 	args ForecastArgs,
 	expectedResponse interface{},
+) (err error) {
+	args = c.withDefaults(args)
+	if err := args.Validate(endpt); err != nil {
+		return err
+	}
+
+	req := &Request{
+		Endpoint: endpt,
+		Args:     args,
+		Header:   make(http.Header),
+		baseURL:  c.baseURL,
+	}
+
+	if c.tracer != nil {
+		var span Span
+		ctx, span = c.startSpan(ctx, endpt)
+		setForecastArgsAttributes(span, req.Args)
+		defer func() {
+			// spans for streamed requests end once the whole response is
+			// read in, along with the request's log and metrics
+			stream, ok := expectedResponse.(*sampleStream)
+			if ok && err == nil && stream.finish != nil {
+				finish := stream.finish
+				stream.finish = func(err error) {
+					finish(err)
+					endSpan(span, err)
+				}
+				return
+			}
+			endSpan(span, err)
+		}()
+	}
+
+	// streamed requests skip the Client's middleware, since their results
+	// aren't the type returned by a Client method, and are only read in after
+	// the middleware would have returned
+	if _, streaming := expectedResponse.(*sampleStream); streaming {
+		return c.handleWeatherRequest(ctx, req, expectedResponse)
+	}
+	return chainMiddleware(c.handleWeatherRequest, c.middleware)(ctx, req, expectedResponse)
+}
+
+// handleWeatherRequest is the Handler at the end of the Client's middleware
+// chain, which gets weather data from the cache, or from the API on a cache
+// miss.
+func (c *Client) handleWeatherRequest(
+	ctx context.Context,
+	req *Request,
+	expectedResponse interface{},
 ) error {
-	u, err := url.Parse(c.baseURL)
+	apiReq := &apiRequest{
+		method: http.MethodGet,
+		endpt:  req.Endpoint,
+		path:   string(req.Endpoint),
+		query:  req.Args.QueryParams(),
+		header: req.Header,
+	}
+
+	// streamed responses are not cached, since they are never read in full
+	// before being returned
+	_, streaming := expectedResponse.(*sampleStream)
+	ttl := c.cacheTTL(req.Endpoint)
+	if ttl <= 0 || streaming {
+		return c.fetch(ctx, apiReq, expectedResponse)
+	}
+
+	key := c.cacheKey(req.Endpoint, req.Args)
+	if b, ok := c.cache.Get(key); ok && json.Unmarshal(b, expectedResponse) == nil {
+		atomic.AddUint64(&c.cacheHits, 1)
+		if c.metrics != nil {
+			c.metrics.IncCacheHits(req.Endpoint)
+		}
+		return nil
+	}
+	atomic.AddUint64(&c.cacheMisses, 1)
+	if c.metrics != nil {
+		c.metrics.IncCacheMisses(req.Endpoint)
+	}
+
+	var raw json.RawMessage
+	if err := c.fetch(ctx, apiReq, &raw); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, expectedResponse); err != nil {
+		if c.metrics != nil {
+			c.metrics.IncDecodeErrors(req.Endpoint)
+		}
+		return errors.WithMessage(err, "deserializing weather response data")
+	}
+	c.cache.Set(key, raw, ttl)
+	return nil
+}
+
+// apiRequest contains what's needed for sending a request to the API.
+type apiRequest struct {
+	// the HTTP method to send the request with
+	method string
+	// the endpoint the request is for, which the request is labeled with in
+	// logs, metrics, and traces
+	endpt Endpoint
+	// the path to send the request to, relative to the base URL, with any
+	// IDs in it escaped. Usually the same as endpt, but for endpoints like
+	// locations/{id}, endpt is "locations" and the path contains the ID.
+	path string
+	// the query parameters and extra headers to send with the request
+	query  url.Values
+	header http.Header
+	// if non-nil, the request body, which is serialized to JSON
+	body interface{}
+	// whether the request is to v4 of the API rather than v3
+	v4 bool
+	// whether the request only reads data, so it's safe to retry even if
+	// its method is POST
+	readOnly bool
+}
+
+// call sends a request to an endpoint other than the weather data endpoints,
+// tracing it if the Client has a Tracer.
+func (c *Client) call(ctx context.Context, req *apiRequest, expectedResponse interface{}) (err error) {
+	if c.tracer != nil {
+		var span Span
+		ctx, span = c.startSpan(ctx, req.endpt)
+		defer func() { endSpan(span, err) }()
+	}
+	return c.fetch(ctx, req, expectedResponse)
+}
+
+// fetch sends a request to the API, waiting on the Client's rate limiter and
+// retrying per its retry policy.
+func (c *Client) fetch(ctx context.Context, req *apiRequest, expectedResponse interface{}) error {
+	canRetry := req.readOnly || idempotent(req.method)
+	return c.withRetries(ctx, req.endpt, canRetry, func() (http.Header, error) {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return c.send(ctx, req, expectedResponse)
+	})
+}
+
+// send sends a single request to the API, deserializing the response into
+// expectedResponse. The response's headers are returned alongside any error
+// so callers can inspect headers like Retry-After.
+func (c *Client) send(
+	ctx context.Context,
+	apiReq *apiRequest,
+	expectedResponse interface{},
+) (http.Header, error) {
+	endpt := apiReq.endpt
+	baseURL := c.baseURL
+	if apiReq.v4 {
+		baseURL = c.v4BaseURL
+	}
+	u, err := url.Parse(baseURL)
 	if err != nil {
-		return errors.WithMessage(err, "parsing base URL")
+		return nil, errors.WithMessage(err, "parsing base URL")
 	}
-	u = u.ResolveReference(&url.URL{Path: endpt})
+	// paths can contain escaped IDs, so they're parsed rather than used as
+	// url.URL.Path, which would escape them again
+	ref, err := url.Parse(apiReq.path)
+	if err != nil {
+		return nil, errors.WithMessage(err, "parsing request path")
+	}
+	u = u.ResolveReference(ref)
 
-	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	var body io.Reader
+	if apiReq.body != nil {
+		b, err := json.Marshal(apiReq.body)
+		if err != nil {
+			return nil, errors.WithMessage(err, "serializing request body")
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, apiReq.method, u.String(), body)
 	if err != nil {
-		return errors.WithMessage(err, "making HTTP request")
+		return nil, errors.WithMessage(err, "making HTTP request")
+	}
+	for k, v := range apiReq.header {
+		req.Header[k] = v
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
 	}
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("apikey", c.apiKey)
-	req.URL.RawQuery = args.QueryParams().Encode()
+	req.Header.Set("apikey", c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.tracer != nil {
+		c.tracer.Inject(ctx, req.Header)
+	}
+	req.URL.RawQuery = apiReq.query.Encode()
 
+	start := time.Now()
 	res, err := c.c.Do(req)
 	if err != nil {
-		return errors.WithMessagef(err, "sending weather data request to %s", endpt)
+		latency := time.Since(start)
+		c.logRequest(newRequestLog(req, endpt, nil, latency, 0, err))
+		if c.metrics != nil {
+			c.metrics.ObserveRequest(endpt, 0, latency)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, errors.WithMessagef(ctxErr, "sending request to %s", endpt)
+		}
+		return nil, errors.WithMessagef(err, "sending request to %s", endpt)
+	}
+	resBody := &countingReadCloser{ReadCloser: res.Body}
+	res.Body = resBody
+
+	decodeFailed, err := decodeResponse(ctx, res, apiReq.v4, expectedResponse)
+	if span := spanFromContext(ctx); span != nil {
+		span.SetAttribute(AttrStatusCode, res.StatusCode)
 	}
-	defer res.Body.Close()
 
+	// streamed responses are closed by their iterator instead, which logs
+	// the request once the whole response is read in, so its latency and
+	// bytes received cover the whole response
+	if stream, ok := expectedResponse.(*sampleStream); ok && stream.body != nil {
+		stream.finish = func(err error) {
+			c.observeRequest(req, endpt, res, time.Since(start), resBody.n, err)
+		}
+		return res.Header, err
+	}
+	res.Body.Close()
+	c.observeRequest(req, endpt, res, time.Since(start), resBody.n, err)
+	if c.metrics != nil && decodeFailed {
+		c.metrics.IncDecodeErrors(endpt)
+	}
+	return res.Header, err
+}
+
+// observeRequest logs a request that got a response, and records its metrics.
+func (c *Client) observeRequest(
+	req *http.Request,
+	endpt Endpoint,
+	res *http.Response,
+	latency time.Duration,
+	bytesReceived int64,
+	err error,
+) {
+	c.logRequest(newRequestLog(req, endpt, res, latency, bytesReceived, err))
+	if c.metrics != nil {
+		c.metrics.ObserveRequest(endpt, res.StatusCode, latency)
+	}
+}
+
+// rawResponse is an expectedResponse for responses that are not JSON, such as
+// map tiles, which are read in as-is along with their content type.
+type rawResponse struct {
+	body        []byte
+	contentType string
+}
+
+// decodeResponse deserializes a response from the API into expectedResponse
+// on a 200 or 201, or returns the error for the response's status code.
+// expectedResponse can be nil for requests whose responses have no body. v4
+// indicates whether the response is from v4 of the API, which has a different
+// format for error responses. decodeFailed is true if the error is from
+// failing to deserialize the response body. If expectedResponse is a
+// *rawResponse, the body is read in as-is instead of being deserialized, and
+// if it is a *sampleStream, the stream is opened for reading the body.
+func decodeResponse(
+	ctx context.Context,
+	res *http.Response,
+	v4 bool,
+	expectedResponse interface{},
+) (decodeFailed bool, err error) {
 	switch res.StatusCode {
-	case 200:
+	case 200, 201, 204:
+		if expectedResponse == nil || res.StatusCode == 204 {
+			return false, nil
+		}
+		if stream, ok := expectedResponse.(*sampleStream); ok {
+			return stream.open(ctx, res.Body)
+		}
+		if raw, ok := expectedResponse.(*rawResponse); ok {
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return false, errors.WithMessage(ctxErr, "reading response data")
+				}
+				return false, errors.WithMessage(err, "reading response data")
+			}
+			raw.body, raw.contentType = body, res.Header.Get("Content-Type")
+			return false, nil
+		}
 		if err := json.NewDecoder(res.Body).Decode(expectedResponse); err != nil {
-			return errors.WithMessage(err, "deserializing weather response data")
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return false, errors.WithMessage(ctxErr, "reading response data")
+			}
+			return true, errors.WithMessage(err, "deserializing response data")
 		}
-		return nil
+		return false, nil
 	case 400, 401, 403, 404, 500:
-		var errRes ErrorResponse
-		if err := json.NewDecoder(res.Body).Decode(&errRes); err != nil {
-			return errors.WithMessage(err, "deserializing weather error response")
+		if v4 {
+			var errRes v4ErrorResponse
+			if err := json.NewDecoder(res.Body).Decode(&errRes); err != nil {
+				return true, undecodableErrorResponse(res, err)
+			}
+			return false, errRes.toErrorResponse(res.StatusCode)
 		}
 
-		if res.StatusCode == 401 || res.StatusCode == 403 {
-			errRes.StatusCode = res.StatusCode
+		var errRes ErrorResponse
+		if err := json.NewDecoder(res.Body).Decode(&errRes); err != nil {
+			return true, undecodableErrorResponse(res, err)
 		}
-		return &errRes
+		errRes.StatusCode = res.StatusCode
+		return false, &errRes
+	case 429:
+		return false, newRateLimitError(res)
 	default:
-		return fmt.Errorf("unexpected HTTP response status code: %d", res.StatusCode)
+		return false, &UnexpectedStatusError{StatusCode: res.StatusCode}
 	}
 }
 
+// undecodableErrorResponse returns the error for an error response whose body
+// could not be deserialized, such as an HTML page from a proxy. Its cause is an
+// UnexpectedStatusError, so the response's status code can still be checked,
+// such as for retrying 500 errors.
+func undecodableErrorResponse(res *http.Response, err error) error {
+	return errors.WithMessagef(&UnexpectedStatusError{StatusCode: res.StatusCode},
+		"deserializing error response (%v)", err)
+}
+
 // ErrorResponse returns errors for 400, 401, 403, and 500 errors.
 type ErrorResponse struct {
 	// StatusCode indicates the HTTP status for this errored API request.
-	// For 401 and 403 errors, this is not present in the actual API
-	// response's JSON, so this is filled in for us.
+	// It is always the response's status code, since it is not present in
+	// the API response's JSON for 401 and 403 errors, and might not be for
+	// other errors.
 	StatusCode int `json:"statusCode"`
 	// ErrorCode is the error code for this request. Not present on 401 and
 	// 403 errors.
@@ -226,3 +638,16 @@ func (err *ErrorResponse) Error() string {
 	}
 	return fmt.Sprintf("%d (%s) API error: %s", err.StatusCode, err.ErrorCode, err.Message)
 }
+
+// UnexpectedStatusError is returned when the ClimaCell API responds with an
+// HTTP status code that does not come with an ErrorResponse, such as a 502 or
+// 503 from a gateway in front of the API, or with an error response whose body
+// could not be deserialized.
+type UnexpectedStatusError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+}
+
+func (err *UnexpectedStatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP response status code: %d", err.StatusCode)
+}
@@ -1,6 +1,7 @@
 package climacell
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
@@ -9,6 +10,8 @@ import (
 	"strconv"
 	"testing"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 func hourlyForecastHandler() http.Handler {
@@ -268,3 +271,25 @@ func TestRealTimeEndpoint(t *testing.T) {
 		t.Errorf("Did not get expected result. Wanted %f, got: %f\n", expectedTemp, value)
 	}
 }
+
+func TestRequestCancelledWithContext(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	client := New("test_api_key")
+	client.baseURL = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.HourlyForecastWithContext(ctx, ForecastArgs{
+		Location: LatLon{Lat: 11.3, Lon: 52.4},
+	})
+	if errors.Cause(err) != context.DeadlineExceeded {
+		t.Errorf("Expected the cause of the error to be %v, got: %v", context.DeadlineExceeded, err)
+	}
+}
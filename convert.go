@@ -0,0 +1,69 @@
+package climacell
+
+import (
+	"github.com/andyhaskell/climacell-go/units"
+	"github.com/pkg/errors"
+)
+
+// ConvertTo returns a copy of the FloatValue converted to another unit of
+// measure, with its Units set to the unit. For example, a temperature of 10
+// with Units "C" converted to units.Fahrenheit is 50 with Units "F". An error
+// is returned if the FloatValue's Units can't be parsed by units.Parse, or
+// can't be converted to the unit, such as when converting a temperature to a
+// speed. If the FloatValue is nil, nil is returned, and if its Value is nil,
+// only its Units are changed.
+func (f *FloatValue) ConvertTo(to units.Unit) (*FloatValue, error) {
+	if f == nil {
+		return nil, nil
+	}
+	from, err := units.Parse(f.Units)
+	if err != nil {
+		return nil, err
+	}
+	if from.Dimension() != to.Dimension() {
+		return nil, errors.Errorf("cannot convert %s to %s", f.Units, to)
+	}
+	if f.Value == nil {
+		return &FloatValue{Units: string(to)}, nil
+	}
+
+	v, err := units.Convert(*f.Value, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &FloatValue{Value: &v, Units: string(to)}, nil
+}
+
+// ConvertTo returns a copy of the FloatAtTimeValue with its Value converted
+// to another unit of measure with FloatValue.ConvertTo.
+func (f *FloatAtTimeValue) ConvertTo(to units.Unit) (*FloatAtTimeValue, error) {
+	if f == nil {
+		return nil, nil
+	}
+	v, err := f.Value.ConvertTo(to)
+	if err != nil {
+		return nil, err
+	}
+	return &FloatAtTimeValue{ObservationTime: f.ObservationTime, Value: v}, nil
+}
+
+// ConvertTo returns a copy of the ForecastMinAndMax with its minimum and
+// maximum converted to another unit of measure with FloatValue.ConvertTo.
+func (f ForecastMinAndMax) ConvertTo(to units.Unit) (ForecastMinAndMax, error) {
+	if f == nil {
+		return nil, nil
+	}
+	converted := make(ForecastMinAndMax, len(f))
+	for i, v := range f {
+		min, err := v.Min.ConvertTo(to)
+		if err != nil {
+			return nil, errors.WithMessage(err, "converting minimum")
+		}
+		max, err := v.Max.ConvertTo(to)
+		if err != nil {
+			return nil, errors.WithMessage(err, "converting maximum")
+		}
+		converted[i] = ForecastJSONMinMax{ObservationTime: v.ObservationTime, Min: min, Max: max}
+	}
+	return converted, nil
+}
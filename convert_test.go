@@ -0,0 +1,67 @@
+package climacell
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyhaskell/climacell-go/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFloatValueConvertTo(t *testing.T) {
+	temp := 10.0
+	f := &FloatValue{Value: &temp, Units: "C"}
+
+	converted, err := f.ConvertTo(units.Fahrenheit)
+	require.NoError(t, err)
+	assert.Equal(t, "F", converted.Units)
+	v, ok := converted.GetValue()
+	assert.True(t, ok)
+	assert.InDelta(t, 50, v, 1e-9)
+
+	// the original FloatValue is unchanged
+	assert.Equal(t, 10.0, temp)
+	assert.Equal(t, "C", f.Units)
+
+	_, err = f.ConvertTo(units.MilesPerHour)
+	assert.Error(t, err)
+
+	converted, err = (&FloatValue{Units: "mph"}).ConvertTo(units.MetersPerSecond)
+	require.NoError(t, err)
+	assert.Equal(t, &FloatValue{Units: "m/s"}, converted)
+
+	var nilValue *FloatValue
+	converted, err = nilValue.ConvertTo(units.Celsius)
+	assert.NoError(t, err)
+	assert.Nil(t, converted)
+}
+
+func TestForecastMinAndMaxConvertTo(t *testing.T) {
+	min, max := 1013.25, 1020.0
+	day := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	f := ForecastMinAndMax{
+		{ObservationTime: day, Min: &FloatValue{Value: &min, Units: "hPa"}},
+		{ObservationTime: day.Add(time.Hour), Max: &FloatValue{Value: &max, Units: "hPa"}},
+	}
+
+	converted, err := f.ConvertTo(units.InchesOfMercury)
+	require.NoError(t, err)
+
+	convertedMin := converted.Min()
+	require.NotNil(t, convertedMin)
+	assert.Equal(t, day, convertedMin.ObservationTime)
+	v, _ := convertedMin.GetValue()
+	assert.InDelta(t, 29.9212, v, 1e-4)
+	u, _ := convertedMin.GetUnits()
+	assert.Equal(t, "inHg", u)
+
+	maxAt, err := f.Max().ConvertTo(units.Kilopascals)
+	require.NoError(t, err)
+	assert.Equal(t, day.Add(time.Hour), maxAt.ObservationTime)
+	v, _ = maxAt.GetValue()
+	assert.InDelta(t, 102, v, 1e-9)
+
+	_, err = f.ConvertTo(units.Celsius)
+	assert.Error(t, err)
+}
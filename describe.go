@@ -0,0 +1,189 @@
+package climacell
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Catalog translates the descriptions of enum values, like weather codes, to
+// a language. Keys are the enum's kind and value joined by a period, such as
+// "weather_code.ice_pellets_light", "precipitation_type.freezing rain",
+// "moon_phase.full", or "road_risk.high_risk".
+type Catalog interface {
+	// Lookup returns the description for a key, and whether the catalog
+	// has one.
+	Lookup(key string) (string, bool)
+}
+
+// MapCatalog is a Catalog backed by a map from keys to descriptions.
+type MapCatalog map[string]string
+
+// Lookup implements the Catalog interface.
+func (c MapCatalog) Lookup(key string) (string, bool) {
+	s, ok := c[key]
+	return s, ok
+}
+
+var catalogs = struct {
+	sync.RWMutex
+	m map[string]Catalog
+}{m: make(map[string]Catalog)}
+
+// RegisterCatalog registers the Catalog used for describing enum values in a
+// locale, such as "es" or "pt-BR", replacing any Catalog already registered
+// for it. English descriptions are built in, and are used for any value a
+// locale's Catalog does not have.
+func RegisterCatalog(locale string, c Catalog) {
+	catalogs.Lock()
+	defer catalogs.Unlock()
+	catalogs.m[normalizeLocale(locale)] = c
+}
+
+// normalizeLocale lowercases a locale and uses hyphens as separators, so
+// "pt_BR" and "pt-br" are the same locale.
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(locale, "_", "-", -1))
+}
+
+// describe returns the description of an enum value in a locale. The locale's
+// Catalog is checked first, then the Catalog for its language without a
+// region, such as "es" for "es-MX", falling back to the English label.
+func describe(kind, value, locale string, english enum) string {
+	key := kind + "." + value
+	locale = normalizeLocale(locale)
+
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+	for locale != "" {
+		if c, ok := catalogs.m[locale]; ok {
+			if s, ok := c.Lookup(key); ok {
+				return s
+			}
+		}
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return english.label(value)
+}
+
+// Describe returns a description of the weather code in a locale, such as
+// "Light Ice Pellets" in English, using the Catalog registered for the
+// locale with RegisterCatalog.
+func (c WeatherCode) Describe(locale string) string {
+	return describe("weather_code", string(c), locale, weatherCodes)
+}
+
+// Describe returns a description of the precipitation type in a locale, like
+// WeatherCode.Describe.
+func (t PrecipitationType) Describe(locale string) string {
+	return describe("precipitation_type", string(t), locale, precipitationTypes)
+}
+
+// Describe returns a description of the moon phase in a locale, like
+// WeatherCode.Describe.
+func (p MoonPhase) Describe(locale string) string {
+	return describe("moon_phase", string(p), locale, moonPhases)
+}
+
+// Describe returns a description of the road risk in a locale, like
+// WeatherCode.Describe.
+func (r RoadRisk) Describe(locale string) string {
+	return describe("road_risk", string(r), locale, roadRisks)
+}
+
+// iconsWithNightVariants are the weather codes whose icons have day and night
+// variants, since the sun or moon shows through.
+var iconsWithNightVariants = map[WeatherCode]bool{
+	WeatherCodeClear:        true,
+	WeatherCodeMostlyClear:  true,
+	WeatherCodePartlyCloudy: true,
+	WeatherCodeMostlyCloudy: true,
+}
+
+// Icon returns the name of the icon for the weather code, which is the
+// weather code itself, with a "_day" or "_night" suffix for codes like
+// WeatherCodeClear whose icons have day and night variants. For example, the
+// icon for WeatherCodePartlyCloudy at night is "partly_cloudy_night", and
+// the icon for WeatherCodeRain is "rain".
+func (c WeatherCode) Icon(daytime bool) string {
+	if !iconsWithNightVariants[c] {
+		return string(c)
+	}
+	if daytime {
+		return string(c) + "_day"
+	}
+	return string(c) + "_night"
+}
+
+// IsDaytime returns whether a time is between sunrise and sunset. Only the
+// times of day are compared, so sunrise and sunset can be from a different
+// day than t, like on samples further out in an hourly forecast. If sunrise
+// or sunset is absent, IsDaytime returns true.
+func IsDaytime(t time.Time, sunrise, sunset *TimeValue) bool {
+	rise, ok := sunrise.GetValue()
+	if !ok {
+		return true
+	}
+	set, ok := sunset.GetValue()
+	if !ok {
+		return true
+	}
+
+	now, riseAt, setAt := timeOfDay(t), timeOfDay(rise), timeOfDay(set)
+	if riseAt <= setAt {
+		return riseAt <= now && now < setAt
+	}
+	// in UTC, sunset can be earlier in the day than sunrise, such as in
+	// the Americas, where the sun sets after midnight UTC
+	return now >= riseAt || now < setAt
+}
+
+func timeOfDay(t time.Time) time.Duration {
+	t = t.UTC()
+	return time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+}
+
+// IconAt returns the name of the icon for a weather sample's weather code,
+// with its day or night variant picked by whether observed is between its
+// Sunrise and Sunset. If the sample has no weather code, a blank string is
+// returned.
+func (w WeatherType) IconAt(observed time.Time) string {
+	code, ok := w.WeatherCode.GetValue()
+	if !ok {
+		return ""
+	}
+	return code.Icon(IsDaytime(observed, w.Sunrise, w.Sunset))
+}
+
+// Icon returns the name of the icon for the sample's weather code, with its
+// day or night variant picked by its ObservationTime like with
+// WeatherType.IconAt.
+func (f NowCastForecast) Icon() string { return f.IconAt(f.ObservationTime.Value) }
+
+// Icon is like NowCastForecast.Icon.
+func (f HourlyForecast) Icon() string { return f.IconAt(f.ObservationTime.Value) }
+
+// Icon is like NowCastForecast.Icon.
+func (f RealTime) Icon() string { return f.IconAt(f.ObservationTime.Value) }
+
+// Icon is like NowCastForecast.Icon.
+func (f HistoricalClimaCell) Icon() string { return f.IconAt(f.ObservationTime.Value) }
+
+// Icon is like NowCastForecast.Icon.
+func (f HistoricalStation) Icon() string { return f.IconAt(f.ObservationTime.Value) }
+
+// Icon returns the name of the icon for the day's weather code. Since a
+// ForecastDay is a summary of the day, the day variant of the icon is used.
+func (d ForecastDay) Icon() string {
+	code, ok := d.WeatherCode.GetValue()
+	if !ok {
+		return ""
+	}
+	return code.Icon(true)
+}
@@ -0,0 +1,77 @@
+package climacell
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribe(t *testing.T) {
+	RegisterCatalog("es", MapCatalog{
+		"weather_code.ice_pellets_light": "Granizo ligero",
+		"weather_code.mostly_clear":      "Mayormente despejado",
+		"road_risk.high_risk":            "Riesgo alto",
+	})
+	RegisterCatalog("es_MX", MapCatalog{
+		"weather_code.mostly_clear": "Casi despejado",
+	})
+	defer func() {
+		catalogs.Lock()
+		delete(catalogs.m, "es")
+		delete(catalogs.m, "es-mx")
+		catalogs.Unlock()
+	}()
+
+	assert.Equal(t, "Mostly Clear", WeatherCodeMostlyClear.Describe("en"))
+	assert.Equal(t, "Mostly Clear", WeatherCodeMostlyClear.Describe(""))
+	assert.Equal(t, "Granizo ligero", WeatherCodeIcePelletsLight.Describe("es"))
+	assert.Equal(t, "Casi despejado", WeatherCodeMostlyClear.Describe("es-MX"))
+	assert.Equal(t, "Mayormente despejado", WeatherCodeMostlyClear.Describe("es-ES"))
+	// falls back to the language without a region
+	assert.Equal(t, "Granizo ligero", WeatherCodeIcePelletsLight.Describe("es-MX"))
+	assert.Equal(t, "Riesgo alto", RoadRiskHigh.Describe("ES"))
+	// falls back to English
+	assert.Equal(t, "Heavy Snow", WeatherCodeSnowHeavy.Describe("es"))
+	assert.Equal(t, "Freezing Rain", PrecipitationTypeFreezingRain.Describe("es"))
+	assert.Equal(t, "Waning Crescent", MoonPhaseWaningCrescent.Describe("fr"))
+}
+
+func TestIsDaytime(t *testing.T) {
+	sunrise := &TimeValue{Value: timePtr(time.Date(2020, 9, 1, 10, 30, 0, 0, time.UTC))}
+	sunset := &TimeValue{Value: timePtr(time.Date(2020, 9, 1, 23, 15, 0, 0, time.UTC))}
+
+	assert.True(t, IsDaytime(time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC), sunrise, sunset))
+	assert.False(t, IsDaytime(time.Date(2020, 9, 1, 23, 30, 0, 0, time.UTC), sunrise, sunset))
+	assert.False(t, IsDaytime(time.Date(2020, 9, 3, 4, 0, 0, 0, time.UTC), sunrise, sunset),
+		"times on other days compare by time of day")
+
+	// sunset after midnight UTC
+	sunset = &TimeValue{Value: timePtr(time.Date(2020, 9, 2, 0, 30, 0, 0, time.UTC))}
+	assert.True(t, IsDaytime(time.Date(2020, 9, 2, 0, 15, 0, 0, time.UTC), sunrise, sunset))
+	assert.False(t, IsDaytime(time.Date(2020, 9, 2, 5, 0, 0, 0, time.UTC), sunrise, sunset))
+
+	assert.True(t, IsDaytime(time.Now(), nil, sunset))
+}
+
+func TestIcon(t *testing.T) {
+	assert.Equal(t, "clear_day", WeatherCodeClear.Icon(true))
+	assert.Equal(t, "partly_cloudy_night", WeatherCodePartlyCloudy.Icon(false))
+	assert.Equal(t, "rain", WeatherCodeRain.Icon(false))
+
+	var f HourlyForecast
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"observation_time": {"value": "2020-09-02T03:00:00Z"},
+		"weather_code":     {"value": "mostly_clear"},
+		"sunrise":          {"value": "2020-09-01T10:30:00Z"},
+		"sunset":           {"value": "2020-09-01T23:15:00Z"}
+	}`), &f))
+	assert.Equal(t, "mostly_clear_night", f.Icon())
+	assert.Equal(t, "mostly_clear_day", f.IconAt(time.Date(2020, 9, 2, 15, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, "", HourlyForecast{}.Icon())
+}
+
+func timePtr(t time.Time) *time.Time { return &t }
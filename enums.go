@@ -0,0 +1,387 @@
+package climacell
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WeatherCode is a text description of the weather, such as
+// WeatherCodeMostlyClear. Values the API returns that are not one of the
+// WeatherCode constants are kept as-is, so Known returns false for them.
+type WeatherCode string
+
+// The weather codes the ClimaCell API returns, from least to most severe.
+const (
+	WeatherCodeClear             WeatherCode = "clear"
+	WeatherCodeMostlyClear       WeatherCode = "mostly_clear"
+	WeatherCodePartlyCloudy      WeatherCode = "partly_cloudy"
+	WeatherCodeMostlyCloudy      WeatherCode = "mostly_cloudy"
+	WeatherCodeCloudy            WeatherCode = "cloudy"
+	WeatherCodeFogLight          WeatherCode = "fog_light"
+	WeatherCodeFog               WeatherCode = "fog"
+	WeatherCodeDrizzle           WeatherCode = "drizzle"
+	WeatherCodeRainLight         WeatherCode = "rain_light"
+	WeatherCodeRain              WeatherCode = "rain"
+	WeatherCodeRainHeavy         WeatherCode = "rain_heavy"
+	WeatherCodeFlurries          WeatherCode = "flurries"
+	WeatherCodeSnowLight         WeatherCode = "snow_light"
+	WeatherCodeSnow              WeatherCode = "snow"
+	WeatherCodeSnowHeavy         WeatherCode = "snow_heavy"
+	WeatherCodeFreezingDrizzle   WeatherCode = "freezing_drizzle"
+	WeatherCodeFreezingRainLight WeatherCode = "freezing_rain_light"
+	WeatherCodeFreezingRain      WeatherCode = "freezing_rain"
+	WeatherCodeFreezingRainHeavy WeatherCode = "freezing_rain_heavy"
+	WeatherCodeIcePelletsLight   WeatherCode = "ice_pellets_light"
+	WeatherCodeIcePellets        WeatherCode = "ice_pellets"
+	WeatherCodeIcePelletsHeavy   WeatherCode = "ice_pellets_heavy"
+	WeatherCodeThunderstorm      WeatherCode = "tstorm"
+)
+
+var weatherCodes = newEnum(
+	enumValue{string(WeatherCodeClear), "Clear"},
+	enumValue{string(WeatherCodeMostlyClear), "Mostly Clear"},
+	enumValue{string(WeatherCodePartlyCloudy), "Partly Cloudy"},
+	enumValue{string(WeatherCodeMostlyCloudy), "Mostly Cloudy"},
+	enumValue{string(WeatherCodeCloudy), "Cloudy"},
+	enumValue{string(WeatherCodeFogLight), "Light Fog"},
+	enumValue{string(WeatherCodeFog), "Fog"},
+	enumValue{string(WeatherCodeDrizzle), "Drizzle"},
+	enumValue{string(WeatherCodeRainLight), "Light Rain"},
+	enumValue{string(WeatherCodeRain), "Rain"},
+	enumValue{string(WeatherCodeRainHeavy), "Heavy Rain"},
+	enumValue{string(WeatherCodeFlurries), "Flurries"},
+	enumValue{string(WeatherCodeSnowLight), "Light Snow"},
+	enumValue{string(WeatherCodeSnow), "Snow"},
+	enumValue{string(WeatherCodeSnowHeavy), "Heavy Snow"},
+	enumValue{string(WeatherCodeFreezingDrizzle), "Freezing Drizzle"},
+	enumValue{string(WeatherCodeFreezingRainLight), "Light Freezing Rain"},
+	enumValue{string(WeatherCodeFreezingRain), "Freezing Rain"},
+	enumValue{string(WeatherCodeFreezingRainHeavy), "Heavy Freezing Rain"},
+	enumValue{string(WeatherCodeIcePelletsLight), "Light Ice Pellets"},
+	enumValue{string(WeatherCodeIcePellets), "Ice Pellets"},
+	enumValue{string(WeatherCodeIcePelletsHeavy), "Heavy Ice Pellets"},
+	enumValue{string(WeatherCodeThunderstorm), "Thunderstorm"},
+)
+
+// Known returns whether the weather code is one of the WeatherCode constants.
+func (c WeatherCode) Known() bool { return weatherCodes.known(string(c)) }
+
+// Label returns a human-readable label for the weather code, such as "Mostly
+// Clear". Unknown weather codes are labeled by title-casing their words.
+func (c WeatherCode) Label() string { return weatherCodes.label(string(c)) }
+
+// Severity returns the weather code's position from least to most severe,
+// starting at 0 for WeatherCodeClear, or -1 for unknown weather codes.
+func (c WeatherCode) Severity() int { return weatherCodes.severity(string(c)) }
+
+// PrecipitationType is the type of precipitation, such as
+// PrecipitationTypeRain. Unknown values are kept as-is, like with WeatherCode.
+type PrecipitationType string
+
+// The precipitation types the ClimaCell API returns, from least to most
+// severe.
+const (
+	PrecipitationTypeNone         PrecipitationType = "none"
+	PrecipitationTypeRain         PrecipitationType = "rain"
+	PrecipitationTypeSnow         PrecipitationType = "snow"
+	PrecipitationTypeIcePellets   PrecipitationType = "ice pellets"
+	PrecipitationTypeFreezingRain PrecipitationType = "freezing rain"
+)
+
+var precipitationTypes = newEnum(
+	enumValue{string(PrecipitationTypeNone), "None"},
+	enumValue{string(PrecipitationTypeRain), "Rain"},
+	enumValue{string(PrecipitationTypeSnow), "Snow"},
+	enumValue{string(PrecipitationTypeIcePellets), "Ice Pellets"},
+	enumValue{string(PrecipitationTypeFreezingRain), "Freezing Rain"},
+)
+
+// Known returns whether the precipitation type is one of the
+// PrecipitationType constants.
+func (t PrecipitationType) Known() bool { return precipitationTypes.known(string(t)) }
+
+// Label returns a human-readable label for the precipitation type, such as
+// "Freezing Rain".
+func (t PrecipitationType) Label() string { return precipitationTypes.label(string(t)) }
+
+// Severity returns the precipitation type's position from least to most
+// severe, starting at 0 for PrecipitationTypeNone, or -1 for unknown types.
+func (t PrecipitationType) Severity() int { return precipitationTypes.severity(string(t)) }
+
+// MoonPhase is the phase of the moon, such as MoonPhaseFull. Unknown values
+// are kept as-is, like with WeatherCode.
+type MoonPhase string
+
+// The moon phases the ClimaCell API returns, in the order of the lunar cycle.
+const (
+	MoonPhaseNew            MoonPhase = "new_moon"
+	MoonPhaseWaxingCrescent MoonPhase = "waxing_crescent"
+	MoonPhaseFirstQuarter   MoonPhase = "first_quarter"
+	MoonPhaseWaxingGibbous  MoonPhase = "waxing_gibbous"
+	MoonPhaseFull           MoonPhase = "full"
+	MoonPhaseWaningGibbous  MoonPhase = "waning_gibbous"
+	MoonPhaseThirdQuarter   MoonPhase = "third_quarter"
+	MoonPhaseWaningCrescent MoonPhase = "waning_crescent"
+)
+
+var moonPhases = newEnum(
+	enumValue{string(MoonPhaseNew), "New Moon"},
+	enumValue{string(MoonPhaseWaxingCrescent), "Waxing Crescent"},
+	enumValue{string(MoonPhaseFirstQuarter), "First Quarter"},
+	enumValue{string(MoonPhaseWaxingGibbous), "Waxing Gibbous"},
+	enumValue{string(MoonPhaseFull), "Full Moon"},
+	enumValue{string(MoonPhaseWaningGibbous), "Waning Gibbous"},
+	enumValue{string(MoonPhaseThirdQuarter), "Third Quarter"},
+	enumValue{string(MoonPhaseWaningCrescent), "Waning Crescent"},
+)
+
+// Known returns whether the moon phase is one of the MoonPhase constants.
+func (p MoonPhase) Known() bool { return moonPhases.known(string(p)) }
+
+// Label returns a human-readable label for the moon phase, such as "Full
+// Moon".
+func (p MoonPhase) Label() string { return moonPhases.label(string(p)) }
+
+// Severity returns the moon phase's position in the lunar cycle, starting at
+// 0 for MoonPhaseNew, or -1 for unknown moon phases. Moon phases have no
+// severity, so they are ordered by the cycle instead, for sorting.
+func (p MoonPhase) Severity() int { return moonPhases.severity(string(p)) }
+
+// RoadRisk is the risk level of road conditions, such as RoadRiskLow. Unknown
+// values are kept as-is, like with WeatherCode.
+type RoadRisk string
+
+// The road risk levels the ClimaCell API returns, from least to most severe.
+const (
+	RoadRiskLow          RoadRisk = "low_risk"
+	RoadRiskModerate     RoadRisk = "moderate_risk"
+	RoadRiskModerateHigh RoadRisk = "mod_hi_risk"
+	RoadRiskHigh         RoadRisk = "high_risk"
+	RoadRiskExtreme      RoadRisk = "extreme_risk"
+)
+
+var roadRisks = newEnum(
+	enumValue{string(RoadRiskLow), "Low Risk"},
+	enumValue{string(RoadRiskModerate), "Moderate Risk"},
+	enumValue{string(RoadRiskModerateHigh), "Moderate to High Risk"},
+	enumValue{string(RoadRiskHigh), "High Risk"},
+	enumValue{string(RoadRiskExtreme), "Extreme Risk"},
+)
+
+// Known returns whether the road risk is one of the RoadRisk constants.
+func (r RoadRisk) Known() bool { return roadRisks.known(string(r)) }
+
+// Label returns a human-readable label for the road risk, such as "High
+// Risk".
+func (r RoadRisk) Label() string { return roadRisks.label(string(r)) }
+
+// Severity returns the road risk's position from least to most severe,
+// starting at 0 for RoadRiskLow, or -1 for unknown road risks.
+func (r RoadRisk) Severity() int { return roadRisks.severity(string(r)) }
+
+// HealthConcern is the level of health concern from air quality, per the US
+// EPA or China MEE standard, such as HealthConcernGood. Unknown values are
+// kept as-is, like with WeatherCode.
+type HealthConcern string
+
+// The health concern levels the ClimaCell API returns, from least to most
+// severe.
+const (
+	HealthConcernGood               HealthConcern = "Good"
+	HealthConcernModerate           HealthConcern = "Moderate"
+	HealthConcernUnhealthySensitive HealthConcern = "Unhealthy for Sensitive Groups"
+	HealthConcernUnhealthy          HealthConcern = "Unhealthy"
+	HealthConcernVeryUnhealthy      HealthConcern = "Very Unhealthy"
+	HealthConcernHazardous          HealthConcern = "Hazardous"
+)
+
+var healthConcerns = newEnum(
+	enumValue{string(HealthConcernGood), "Good"},
+	enumValue{string(HealthConcernModerate), "Moderate"},
+	enumValue{string(HealthConcernUnhealthySensitive), "Unhealthy for Sensitive Groups"},
+	enumValue{string(HealthConcernUnhealthy), "Unhealthy"},
+	enumValue{string(HealthConcernVeryUnhealthy), "Very Unhealthy"},
+	enumValue{string(HealthConcernHazardous), "Hazardous"},
+)
+
+// Known returns whether the health concern is one of the HealthConcern
+// constants.
+func (h HealthConcern) Known() bool { return healthConcerns.known(string(h)) }
+
+// Label returns a human-readable label for the health concern.
+func (h HealthConcern) Label() string { return healthConcerns.label(string(h)) }
+
+// Severity returns the health concern's position from least to most severe,
+// starting at 0 for HealthConcernGood, or -1 for unknown health concerns.
+func (h HealthConcern) Severity() int { return healthConcerns.severity(string(h)) }
+
+// Pollutant is an air pollutant, such as PollutantPM25. Unknown values are
+// kept as-is, like with WeatherCode.
+type Pollutant string
+
+// The primary pollutants the ClimaCell API returns.
+const (
+	PollutantPM25 Pollutant = "pm25"
+	PollutantPM10 Pollutant = "pm10"
+	PollutantO3   Pollutant = "o3"
+	PollutantNO2  Pollutant = "no2"
+	PollutantCO   Pollutant = "co"
+	PollutantSO2  Pollutant = "so2"
+)
+
+var pollutants = newEnum(
+	enumValue{string(PollutantCO), "Carbon Monoxide"},
+	enumValue{string(PollutantSO2), "Sulfur Dioxide"},
+	enumValue{string(PollutantNO2), "Nitrogen Dioxide"},
+	enumValue{string(PollutantO3), "Ozone"},
+	enumValue{string(PollutantPM10), "PM10"},
+	enumValue{string(PollutantPM25), "PM2.5"},
+)
+
+// Known returns whether the pollutant is one of the Pollutant constants.
+func (p Pollutant) Known() bool { return pollutants.known(string(p)) }
+
+// Label returns a human-readable name for the pollutant, such as "Ozone".
+func (p Pollutant) Label() string { return pollutants.label(string(p)) }
+
+// Severity returns the pollutant's position from least to most harmful, by
+// the typical health impact of being the primary pollutant, starting at 0 for
+// PollutantCO, or -1 for unknown pollutants. Fine particulate matter is the
+// most harmful.
+func (p Pollutant) Severity() int { return pollutants.severity(string(p)) }
+
+// The value types below are enum fields on a Weather returned from the
+// ClimaCell API. Like StringValue, each has a GetValue method that returns the
+// field's value and a true "ok" if present, or a blank value and a false "ok"
+// if either the struct or its Value is nil, so you can check for data without
+// checking whether two pointer values are non-nil.
+
+// WeatherCodeValue is a field on a Weather that is a WeatherCode.
+type WeatherCodeValue struct {
+	// Value indicates the weather code for this field on a Weather.
+	Value *WeatherCode `json:"value"`
+}
+
+// GetValue returns the weather code, and whether it is present.
+func (v *WeatherCodeValue) GetValue() (val WeatherCode, ok bool) {
+	if v == nil || v.Value == nil {
+		return "", false
+	}
+	return *v.Value, true
+}
+
+// PrecipitationTypeValue is a field on a Weather that is a PrecipitationType.
+type PrecipitationTypeValue struct {
+	// Value indicates the precipitation type for this field on a Weather.
+	Value *PrecipitationType `json:"value"`
+}
+
+// GetValue returns the precipitation type, and whether it is present.
+func (v *PrecipitationTypeValue) GetValue() (val PrecipitationType, ok bool) {
+	if v == nil || v.Value == nil {
+		return "", false
+	}
+	return *v.Value, true
+}
+
+// MoonPhaseValue is a field on a Weather that is a MoonPhase.
+type MoonPhaseValue struct {
+	// Value indicates the moon phase for this field on a Weather.
+	Value *MoonPhase `json:"value"`
+}
+
+// GetValue returns the moon phase, and whether it is present.
+func (v *MoonPhaseValue) GetValue() (val MoonPhase, ok bool) {
+	if v == nil || v.Value == nil {
+		return "", false
+	}
+	return *v.Value, true
+}
+
+// RoadRiskValue is a field on a Weather that is a RoadRisk.
+type RoadRiskValue struct {
+	// Value indicates the road risk for this field on a Weather.
+	Value *RoadRisk `json:"value"`
+}
+
+// GetValue returns the road risk, and whether it is present.
+func (v *RoadRiskValue) GetValue() (val RoadRisk, ok bool) {
+	if v == nil || v.Value == nil {
+		return "", false
+	}
+	return *v.Value, true
+}
+
+// HealthConcernValue is a field on a Weather that is a HealthConcern.
+type HealthConcernValue struct {
+	// Value indicates the health concern for this field on a Weather.
+	Value *HealthConcern `json:"value"`
+}
+
+// GetValue returns the health concern, and whether it is present.
+func (v *HealthConcernValue) GetValue() (val HealthConcern, ok bool) {
+	if v == nil || v.Value == nil {
+		return "", false
+	}
+	return *v.Value, true
+}
+
+// PollutantValue is a field on a Weather that is a Pollutant.
+type PollutantValue struct {
+	// Value indicates the pollutant for this field on a Weather.
+	Value *Pollutant `json:"value"`
+}
+
+// GetValue returns the pollutant, and whether it is present.
+func (v *PollutantValue) GetValue() (val Pollutant, ok bool) {
+	if v == nil || v.Value == nil {
+		return "", false
+	}
+	return *v.Value, true
+}
+
+// enum is the labels and severity ordering of an enum's known values.
+type enum struct {
+	labels     map[string]string
+	severities map[string]int
+}
+
+type enumValue struct {
+	value, label string
+}
+
+// newEnum returns an enum for the values, in order from least to most
+// severe.
+func newEnum(values ...enumValue) enum {
+	e := enum{labels: make(map[string]string), severities: make(map[string]int)}
+	for i, v := range values {
+		e.labels[v.value] = v.label
+		e.severities[v.value] = i
+	}
+	return e
+}
+
+func (e enum) known(v string) bool {
+	_, ok := e.labels[v]
+	return ok
+}
+
+func (e enum) label(v string) string {
+	if label, ok := e.labels[v]; ok {
+		return label
+	}
+	words := strings.Fields(strings.Replace(v, "_", " ", -1))
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToUpper(r)) + w[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+func (e enum) severity(v string) int {
+	if s, ok := e.severities[v]; ok {
+		return s
+	}
+	return -1
+}
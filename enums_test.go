@@ -0,0 +1,72 @@
+package climacell
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumDeserialization(t *testing.T) {
+	var w HourlyForecast
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"weather_code":          {"value": "freezing_rain_heavy"},
+		"precipitation_type":    {"value": "freezing rain"},
+		"moon_phase":            {"value": "waxing_gibbous"},
+		"road_risk":             {"value": "blizzard_risk"},
+		"epa_health_concern":    {"value": "Unhealthy for Sensitive Groups"},
+		"china_primary_pollutant": {"value": "o3"}
+	}`), &w))
+
+	code, ok := w.WeatherCode.GetValue()
+	assert.True(t, ok)
+	assert.Equal(t, WeatherCodeFreezingRainHeavy, code)
+
+	precipType, _ := w.PrecipitationType.GetValue()
+	assert.Equal(t, PrecipitationTypeFreezingRain, precipType)
+
+	phase, _ := w.MoonPhase.GetValue()
+	assert.Equal(t, MoonPhaseWaxingGibbous, phase)
+
+	concern, _ := w.EPAHealthConcern.GetValue()
+	assert.Equal(t, HealthConcernUnhealthySensitive, concern)
+
+	pollutant, _ := w.ChinaPrimaryPollutant.GetValue()
+	assert.Equal(t, PollutantO3, pollutant)
+
+	// unknown values are kept rather than rejected
+	risk, ok := w.RoadRisk.GetValue()
+	assert.True(t, ok)
+	assert.Equal(t, RoadRisk("blizzard_risk"), risk)
+	assert.False(t, risk.Known())
+	assert.Equal(t, "Blizzard Risk", risk.Label())
+	assert.Equal(t, -1, risk.Severity())
+
+	_, ok = w.EPAPrimaryPollutant.GetValue()
+	assert.False(t, ok)
+}
+
+func TestEnumLabels(t *testing.T) {
+	assert.Equal(t, "Mostly Clear", WeatherCodeMostlyClear.Label())
+	assert.Equal(t, "Light Ice Pellets", WeatherCodeIcePelletsLight.Label())
+	assert.Equal(t, "Thunderstorm", WeatherCodeThunderstorm.Label())
+	assert.Equal(t, "Ice Pellets", PrecipitationTypeIcePellets.Label())
+	assert.Equal(t, "Full Moon", MoonPhaseFull.Label())
+	assert.Equal(t, "Moderate to High Risk", RoadRiskModerateHigh.Label())
+	assert.Equal(t, "Very Unhealthy", HealthConcernVeryUnhealthy.Label())
+	assert.Equal(t, "PM2.5", PollutantPM25.Label())
+	assert.Equal(t, "Sleet Heavy", WeatherCode("sleet_heavy").Label())
+}
+
+func TestEnumSeverity(t *testing.T) {
+	assert.True(t, WeatherCodeClear.Severity() < WeatherCodeRainLight.Severity())
+	assert.True(t, WeatherCodeRainLight.Severity() < WeatherCodeRainHeavy.Severity())
+	assert.True(t, WeatherCodeFreezingRain.Severity() < WeatherCodeThunderstorm.Severity())
+	assert.True(t, PrecipitationTypeNone.Severity() < PrecipitationTypeFreezingRain.Severity())
+	assert.True(t, RoadRiskLow.Severity() < RoadRiskExtreme.Severity())
+	assert.True(t, HealthConcernGood.Severity() < HealthConcernHazardous.Severity())
+	assert.Equal(t, 0, MoonPhaseNew.Severity())
+	assert.Equal(t, 7, MoonPhaseWaningCrescent.Severity())
+	assert.True(t, WeatherCodeClear.Known())
+}
@@ -11,10 +11,20 @@ func main() {
 	var c *climacell.Client
 	c = climacell.New(os.Getenv("CLIMACELL_API_KEY"))
 
+	fields, err := climacell.FieldsFor(climacell.EndpointRealTime,
+		climacell.FieldTemp,
+		climacell.FieldNO2,
+		climacell.FieldRoadRisk,
+		climacell.FieldFireIndex,
+	)
+	if err != nil {
+		log.Fatalf("error choosing fields: %v", err)
+	}
+
 	realTime, err := c.RealTime(climacell.ForecastArgs{
 		Location:   &climacell.LatLon{Lat: 42.3826, Lon: -71.1460},
 		UnitSystem: "si",
-		Fields:     []string{"temp,no2,road_risk,fire_index"},
+		Fields:     fields,
 	})
 	if err != nil {
 		log.Fatalf("error getting forecast data: %v", err)
@@ -0,0 +1,175 @@
+package climacell
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Field is a weather data field that can be requested in a ForecastArgs'
+// Fields, such as FieldTemp. Use FieldsFor to convert Fields to the strings a
+// ForecastArgs takes, checking that an endpoint supports each of them.
+type Field string
+
+// Fields on WeatherType and ForecastDay.
+const (
+	FieldTemp                      Field = "temp"
+	FieldFeelsLike                 Field = "feels_like"
+	FieldDewPoint                  Field = "dewpoint"
+	FieldHumidity                  Field = "humidity"
+	FieldWindSpeed                 Field = "wind_speed"
+	FieldWindDirection             Field = "wind_direction"
+	FieldWindGust                  Field = "wind_gust"
+	FieldBaroPressure              Field = "baro_pressure"
+	FieldPrecipitation             Field = "precipitation"
+	FieldPrecipitationType         Field = "precipitation_type"
+	FieldPrecipitationProbability  Field = "precipitation_probability"
+	FieldPrecipitationAccumulation Field = "precipitation_accumulation"
+	FieldSunrise                   Field = "sunrise"
+	FieldSunset                    Field = "sunset"
+	FieldVisibility                Field = "visibility"
+	FieldCloudCover                Field = "cloud_cover"
+	FieldCloudBase                 Field = "cloud_base"
+	FieldCloudCeiling              Field = "cloud_ceiling"
+	FieldSurfaceShortwaveRadiation Field = "surface_shortwave_radiation"
+	FieldMoonPhase                 Field = "moon_phase"
+	FieldWeatherCode               Field = "weather_code"
+)
+
+// Fields on AirQualityType.
+const (
+	FieldPM25                  Field = "pm25"
+	FieldPM10                  Field = "pm10"
+	FieldO3                    Field = "o3"
+	FieldNO2                   Field = "no2"
+	FieldCO                    Field = "co"
+	FieldSO2                   Field = "so2"
+	FieldEPAAQI                Field = "epa_aqi"
+	FieldEPAPrimaryPollutant   Field = "epa_primary_pollutant"
+	FieldEPAHealthConcern      Field = "epa_health_concern"
+	FieldChinaAQI              Field = "china_aqi"
+	FieldChinaPrimaryPollutant Field = "china_primary_pollutant"
+	FieldChinaHealthConcern    Field = "china_health_concern"
+)
+
+// Fields on FireIndexType and RoadRiskType.
+const (
+	FieldFireIndex          Field = "fire_index"
+	FieldRoadRisk           Field = "road_risk"
+	FieldRoadRiskScore      Field = "road_risk_score"
+	FieldRoadRiskConfidence Field = "road_risk_confidence"
+	FieldRoadRiskConditions Field = "road_risk_conditions"
+)
+
+// The groups of endpoints that support the same fields. Fields on
+// WeatherType, AirQualityType, FireIndexType, and RoadRiskType are supported
+// by the endpoints whose sample types embed those types.
+var (
+	sampleEndpoints = []Endpoint{
+		EndpointNowcast,
+		EndpointHourlyForecast,
+		EndpointRealTime,
+		EndpointHistoricalClimaCell,
+	}
+	weatherEndpoints      = append([]Endpoint{EndpointHistoricalStation}, sampleEndpoints...)
+	dailyWeatherEndpoints = append([]Endpoint{EndpointDailyForecast}, weatherEndpoints...)
+	forecastEndpoints     = []Endpoint{EndpointHourlyForecast, EndpointDailyForecast}
+	dailyEndpoints        = []Endpoint{EndpointDailyForecast}
+)
+
+// fieldEndpoints are the endpoints that support each field, in the order the
+// fields are declared.
+var fieldEndpoints = []struct {
+	field     Field
+	endpoints []Endpoint
+}{
+	{FieldTemp, dailyWeatherEndpoints},
+	{FieldFeelsLike, dailyWeatherEndpoints},
+	{FieldDewPoint, weatherEndpoints},
+	{FieldHumidity, dailyWeatherEndpoints},
+	{FieldWindSpeed, dailyWeatherEndpoints},
+	{FieldWindDirection, dailyWeatherEndpoints},
+	{FieldWindGust, weatherEndpoints},
+	{FieldBaroPressure, dailyWeatherEndpoints},
+	{FieldPrecipitation, dailyWeatherEndpoints},
+	{FieldPrecipitationType, weatherEndpoints},
+	{FieldPrecipitationProbability, forecastEndpoints},
+	{FieldPrecipitationAccumulation, dailyEndpoints},
+	{FieldSunrise, dailyWeatherEndpoints},
+	{FieldSunset, dailyWeatherEndpoints},
+	{FieldVisibility, dailyWeatherEndpoints},
+	{FieldCloudCover, weatherEndpoints},
+	{FieldCloudBase, weatherEndpoints},
+	{FieldCloudCeiling, weatherEndpoints},
+	{FieldSurfaceShortwaveRadiation, weatherEndpoints},
+	{FieldMoonPhase, dailyWeatherEndpoints},
+	{FieldWeatherCode, dailyWeatherEndpoints},
+
+	{FieldPM25, sampleEndpoints},
+	{FieldPM10, sampleEndpoints},
+	{FieldO3, sampleEndpoints},
+	{FieldNO2, sampleEndpoints},
+	{FieldCO, sampleEndpoints},
+	{FieldSO2, sampleEndpoints},
+	{FieldEPAAQI, sampleEndpoints},
+	{FieldEPAPrimaryPollutant, sampleEndpoints},
+	{FieldEPAHealthConcern, sampleEndpoints},
+	{FieldChinaAQI, sampleEndpoints},
+	{FieldChinaPrimaryPollutant, sampleEndpoints},
+	{FieldChinaHealthConcern, sampleEndpoints},
+
+	{FieldFireIndex, sampleEndpoints},
+	{FieldRoadRisk, sampleEndpoints},
+	{FieldRoadRiskScore, sampleEndpoints},
+	{FieldRoadRiskConfidence, sampleEndpoints},
+	{FieldRoadRiskConditions, sampleEndpoints},
+}
+
+// AllFields returns every Field.
+func AllFields() []Field {
+	fields := make([]Field, len(fieldEndpoints))
+	for i, f := range fieldEndpoints {
+		fields[i] = f.field
+	}
+	return fields
+}
+
+// Endpoints returns the endpoints that support the field, or nil if the
+// field is not one of the Field constants.
+func (f Field) Endpoints() []Endpoint {
+	for _, fe := range fieldEndpoints {
+		if fe.field == f {
+			return append([]Endpoint(nil), fe.endpoints...)
+		}
+	}
+	return nil
+}
+
+// SupportedBy returns whether an endpoint supports the field.
+func (f Field) SupportedBy(endpt Endpoint) bool {
+	for _, e := range f.Endpoints() {
+		if e == endpt {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldsFor converts Fields to the strings a ForecastArgs' Fields takes,
+// returning an error if the endpoint does not support any of them. For
+// example, this returns an error for FieldRoadRisk on
+// EndpointHistoricalStation, since station data does not have road risk.
+func FieldsFor(endpt Endpoint, fields ...Field) ([]string, error) {
+	strs := make([]string, len(fields))
+	var unsupported []string
+	for i, f := range fields {
+		if !f.SupportedBy(endpt) {
+			unsupported = append(unsupported, string(f))
+		}
+		strs[i] = string(f)
+	}
+	if len(unsupported) > 0 {
+		return nil, errors.Errorf("fields not supported by %s: %s", endpt, strings.Join(unsupported, ", "))
+	}
+	return strs, nil
+}
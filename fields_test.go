@@ -0,0 +1,68 @@
+package climacell
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonTags returns the JSON tags of a struct type's fields, including the
+// fields of embedded structs.
+func jsonTags(t reflect.Type) []string {
+	var tags []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			tags = append(tags, jsonTags(f.Type)...)
+			continue
+		}
+		if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func TestFieldsCoverSampleTypes(t *testing.T) {
+	// fields that are always in responses, rather than requested
+	notFields := map[string]bool{"lat": true, "lon": true, "location_id": true, "observation_time": true}
+
+	for _, typ := range []interface{}{
+		WeatherType{}, AirQualityType{}, FireIndexType{}, RoadRiskType{}, ForecastDay{},
+	} {
+		for _, tag := range jsonTags(reflect.TypeOf(typ)) {
+			if notFields[tag] {
+				continue
+			}
+			assert.NotEmpty(t, Field(tag).Endpoints(), "%T field %s has no Field", typ, tag)
+		}
+	}
+
+	seen := make(map[Field]bool)
+	for _, f := range AllFields() {
+		assert.False(t, seen[f], "duplicate field %s", f)
+		seen[f] = true
+	}
+}
+
+func TestFieldSupportedBy(t *testing.T) {
+	assert.True(t, FieldRoadRisk.SupportedBy(EndpointHourlyForecast))
+	assert.False(t, FieldRoadRisk.SupportedBy(EndpointHistoricalStation))
+	assert.True(t, FieldTemp.SupportedBy(EndpointHistoricalStation))
+	assert.True(t, FieldPrecipitationAccumulation.SupportedBy(EndpointDailyForecast))
+	assert.False(t, FieldPrecipitationAccumulation.SupportedBy(EndpointHourlyForecast))
+	assert.False(t, Field("not_a_field").SupportedBy(EndpointHourlyForecast))
+}
+
+func TestFieldsFor(t *testing.T) {
+	fields, err := FieldsFor(EndpointHistoricalClimaCell, FieldTemp, FieldNO2, FieldRoadRisk)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"temp", "no2", "road_risk"}, fields)
+
+	_, err = FieldsFor(EndpointHistoricalStation, FieldTemp, FieldRoadRisk, FieldFireIndex)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "road_risk, fire_index")
+}
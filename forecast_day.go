@@ -56,7 +56,7 @@ type ForecastDay struct {
 	// The phase of the moon. Values include "new_moon", "waxing_crescent",
 	// "first_quarter", "waxing_gibbous", "full", "waning_gibbous",
 	// "third_quarter", and "waning_crescent"
-	MoonPhase *StringValue `json:"moon_phase"`
+	MoonPhase *MoonPhaseValue `json:"moon_phase"`
 	// A text description of the weather. Possible values include
 	// "freezing_rain_heavy", "freezing_rain", "freezing_rain_light",
 	// "freezing_drizzle", "ice_pellets_heavy", "ice_pellets",
@@ -64,7 +64,7 @@ type ForecastDay struct {
 	// "tstorm", "rain_heavy", "rain", "rain_light", "drizzle",
 	// "fog_light", "fog", "cloudy", "mostly_cloudy", "partly_cloudy",
 	// "mostly_clear", and "clear".
-	WeatherCode *StringValue `json:"weather_code"`
+	WeatherCode *WeatherCodeValue `json:"weather_code"`
 }
 
 // ForecastJSONMinMax is the miniumum or maximum value for a day in a daily
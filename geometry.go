@@ -0,0 +1,225 @@
+package climacell
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// Geometry is a GeoJSON geometry object, such as a Polygon.
+type Geometry struct {
+	// Type is the GeoJSON geometry type, such as "Point" or "Polygon".
+	Type string `json:"type"`
+	// Coordinates contains the geometry's GeoJSON coordinates, in which
+	// each position is a [longitude, latitude] pair.
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// PolygonGeometry returns the GeoJSON Polygon geometry for a ring of points.
+// The ring is normalized like with Polygon.Normalized, so if the ring's last
+// point is not the same as its first, the ring is closed by adding the first
+// point to the end.
+func PolygonGeometry(ring []LatLon) *Geometry {
+	return Polygon{ring}.Normalized().Geometry()
+}
+
+// Polygon is a Location for an area, such as a farm field. Like a GeoJSON
+// Polygon, the first ring is the polygon's exterior, and any other rings are
+// holes in it. Each ring is closed, meaning its last point is the same as its
+// first, and per RFC 7946, exterior rings are counterclockwise, and holes are
+// clockwise. Use Validate to check that a Polygon follows these rules, or
+// Normalized to make a copy of it that does.
+//
+// Note that v3 of the ClimaCell API's weather endpoints only take in points,
+// so polygons are meant for v4 endpoints like Timelines, and for saved
+// locations.
+type Polygon [][]LatLon
+
+// Polyline is a Location for a path, such as a driving route, made of the
+// line segments between each consecutive pair of points.
+type Polyline []LatLon
+
+// LocationQueryParams implements the Location interface, with the polygon's
+// GeoJSON in the "location" query parameter.
+func (p Polygon) LocationQueryParams() url.Values { return geoJSONQueryParams(p) }
+
+// LocationQueryParams implements the Location interface, with the polyline's
+// GeoJSON in the "location" query parameter.
+func (p Polyline) LocationQueryParams() url.Values { return geoJSONQueryParams(p) }
+
+func geoJSONQueryParams(v json.Marshaler) url.Values {
+	b, err := v.MarshalJSON()
+	if err != nil {
+		return url.Values{}
+	}
+	return url.Values{"location": []string{string(b)}}
+}
+
+// Geometry returns the polygon as a GeoJSON Polygon geometry.
+func (p Polygon) Geometry() *Geometry {
+	coords, _ := json.Marshal(p.positions())
+	return &Geometry{Type: "Polygon", Coordinates: coords}
+}
+
+// Geometry returns the polyline as a GeoJSON LineString geometry.
+func (p Polyline) Geometry() *Geometry {
+	coords, _ := json.Marshal(positions(p))
+	return &Geometry{Type: "LineString", Coordinates: coords}
+}
+
+// MarshalJSON serializes a Polygon as a GeoJSON Polygon geometry.
+func (p Polygon) MarshalJSON() ([]byte, error) { return json.Marshal(p.Geometry()) }
+
+// MarshalJSON serializes a Polyline as a GeoJSON LineString geometry.
+func (p Polyline) MarshalJSON() ([]byte, error) { return json.Marshal(p.Geometry()) }
+
+// UnmarshalJSON deserializes a Polygon from a GeoJSON Polygon geometry.
+func (p *Polygon) UnmarshalJSON(b []byte) error {
+	var rings [][][2]float64
+	if err := unmarshalGeometry(b, "Polygon", &rings); err != nil {
+		return err
+	}
+	poly := make(Polygon, len(rings))
+	for i, ring := range rings {
+		poly[i] = latLons(ring)
+	}
+	*p = poly
+	return nil
+}
+
+// UnmarshalJSON deserializes a Polyline from a GeoJSON LineString geometry.
+func (p *Polyline) UnmarshalJSON(b []byte) error {
+	var line [][2]float64
+	if err := unmarshalGeometry(b, "LineString", &line); err != nil {
+		return err
+	}
+	*p = latLons(line)
+	return nil
+}
+
+func unmarshalGeometry(b []byte, typ string, coords interface{}) error {
+	var g Geometry
+	if err := json.Unmarshal(b, &g); err != nil {
+		return err
+	}
+	if g.Type != typ {
+		return errors.Errorf("expected GeoJSON %s, got %q", typ, g.Type)
+	}
+	return json.Unmarshal(g.Coordinates, coords)
+}
+
+// Validate returns an error if the polygon has no rings, if any ring has
+// fewer than four points or is not closed, if any point is out of bounds, or
+// if any ring's winding order does not follow RFC 7946.
+func (p Polygon) Validate() error {
+	if len(p) == 0 {
+		return errors.New("polygon has no rings")
+	}
+	for i, ring := range p {
+		if len(ring) < 4 {
+			return errors.Errorf("ring %d has %d points; rings need at least 4", i, len(ring))
+		}
+		if ring[0] != ring[len(ring)-1] {
+			return errors.Errorf("ring %d is not closed; its last point must be the same as its first", i)
+		}
+		for j, pt := range ring {
+			if err := validateLatLon(pt); err != nil {
+				return errors.WithMessagef(err, "ring %d, point %d", i, j)
+			}
+		}
+
+		area := signedArea(ring)
+		if area == 0 {
+			return errors.Errorf("ring %d has no area", i)
+		}
+		if i == 0 && area < 0 {
+			return errors.New("exterior ring must be counterclockwise")
+		}
+		if i > 0 && area > 0 {
+			return errors.Errorf("ring %d is a hole, so it must be clockwise", i)
+		}
+	}
+	return nil
+}
+
+// Validate returns an error if the polyline has fewer than two points, or if
+// any point is out of bounds.
+func (p Polyline) Validate() error {
+	if len(p) < 2 {
+		return errors.Errorf("polyline has %d points; it needs at least 2", len(p))
+	}
+	for i, pt := range p {
+		if err := validateLatLon(pt); err != nil {
+			return errors.WithMessagef(err, "point %d", i)
+		}
+	}
+	return nil
+}
+
+// Normalized returns a copy of the polygon with each ring closed, and with
+// each ring's winding order reversed if needed to follow RFC 7946.
+func (p Polygon) Normalized() Polygon {
+	norm := make(Polygon, len(p))
+	for i, ring := range p {
+		r := append([]LatLon(nil), ring...)
+		if len(r) > 0 && r[0] != r[len(r)-1] {
+			r = append(r, r[0])
+		}
+
+		area := signedArea(r)
+		if (i == 0 && area < 0) || (i > 0 && area > 0) {
+			for j, k := 0, len(r)-1; j < k; j, k = j+1, k-1 {
+				r[j], r[k] = r[k], r[j]
+			}
+		}
+		norm[i] = r
+	}
+	return norm
+}
+
+func validateLatLon(l LatLon) error {
+	if l.Lat < -90 || l.Lat > 90 {
+		return errors.Errorf("latitude %v is out of bounds", l.Lat)
+	}
+	if l.Lon < -180 || l.Lon > 180 {
+		return errors.Errorf("longitude %v is out of bounds", l.Lon)
+	}
+	return nil
+}
+
+// signedArea returns twice the signed area of a closed ring, treating
+// longitude as x and latitude as y. It is positive for counterclockwise
+// rings, and negative for clockwise rings.
+func signedArea(ring []LatLon) float64 {
+	var sum float64
+	for i := 0; i+1 < len(ring); i++ {
+		sum += ring[i].Lon*ring[i+1].Lat - ring[i+1].Lon*ring[i].Lat
+	}
+	return sum
+}
+
+func (p Polygon) positions() [][][2]float64 {
+	rings := make([][][2]float64, len(p))
+	for i, ring := range p {
+		rings[i] = positions(ring)
+	}
+	return rings
+}
+
+// positions converts points to GeoJSON positions, which are [lon, lat].
+func positions(pts []LatLon) [][2]float64 {
+	pos := make([][2]float64, len(pts))
+	for i, pt := range pts {
+		pos[i] = [2]float64{pt.Lon, pt.Lat}
+	}
+	return pos
+}
+
+func latLons(pos [][2]float64) []LatLon {
+	pts := make([]LatLon, len(pos))
+	for i, p := range pos {
+		pts[i] = LatLon{Lat: p[1], Lon: p[0]}
+	}
+	return pts
+}
@@ -0,0 +1,144 @@
+package climacell
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// square is a counterclockwise unit square with its corner at the origin.
+var square = []LatLon{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}}
+
+func TestPolygonGeoJSON(t *testing.T) {
+	p := Polygon{square}
+	b, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "Polygon",
+		"coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1], [0, 0]]]
+	}`, string(b))
+
+	var decoded Polygon
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, p, decoded)
+
+	var line Polyline
+	assert.Error(t, json.Unmarshal(b, &line), "a Polygon is not a LineString")
+}
+
+func TestPolylineGeoJSON(t *testing.T) {
+	p := Polyline{{Lat: 42.3, Lon: -71.1}, {Lat: 40.7, Lon: -74.0}}
+	b, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "LineString",
+		"coordinates": [[-71.1, 42.3], [-74.0, 40.7]]
+	}`, string(b))
+
+	var decoded Polyline
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, p, decoded)
+}
+
+func TestPolygonValidate(t *testing.T) {
+	clockwise := []LatLon{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}
+	hole := []LatLon{{0.25, 0.25}, {0.75, 0.25}, {0.75, 0.75}, {0.25, 0.75}, {0.25, 0.25}}
+
+	tests := []struct {
+		name    string
+		p       Polygon
+		wantErr bool
+	}{
+		{name: "valid", p: Polygon{square}},
+		{name: "valid with hole", p: Polygon{square, hole}},
+		{name: "no rings", p: Polygon{}, wantErr: true},
+		{name: "too few points", p: Polygon{square[2:]}, wantErr: true},
+		{name: "not closed", p: Polygon{square[:4]}, wantErr: true},
+		{name: "clockwise exterior", p: Polygon{clockwise}, wantErr: true},
+		{name: "counterclockwise hole", p: Polygon{square, square}, wantErr: true},
+		{
+			name:    "out of bounds",
+			p:       Polygon{{{0, 0}, {0, 181}, {1, 181}, {0, 0}}},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.p.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPolygonNormalized(t *testing.T) {
+	clockwiseOpen := []LatLon{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	p := Polygon{clockwiseOpen, square}.Normalized()
+	require.NoError(t, p.Validate())
+	assert.Equal(t, p[0][0], p[0][len(p[0])-1])
+
+	// the original polygon is unchanged
+	assert.Len(t, clockwiseOpen, 4)
+	assert.Equal(t, LatLon{0, 1}, square[1])
+}
+
+func TestPolylineValidate(t *testing.T) {
+	assert.NoError(t, Polyline{{0, 0}, {1, 1}}.Validate())
+	assert.Error(t, Polyline{{0, 0}}.Validate())
+	assert.Error(t, Polyline{{0, 0}, {91, 0}}.Validate())
+}
+
+func TestPolygonTimelinesLocation(t *testing.T) {
+	var body struct {
+		Location json.RawMessage `json:"location"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Write([]byte(`{"data": {"timelines": []}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("test_api_key", WithV4BaseURL(srv.URL))
+	_, err := c.Timelines(context.Background(), TimelinesArgs{
+		Location:  Polygon{square},
+		Fields:    []string{"temperature"},
+		Timesteps: []string{Timestep1Hour},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "Polygon",
+		"coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1], [0, 0]]]
+	}`, string(body.Location))
+}
+
+func TestNewCreateLocationArgs(t *testing.T) {
+	args, err := NewCreateLocationArgs("field", Polygon{square})
+	require.NoError(t, err)
+	assert.Nil(t, args.Point)
+	if assert.NotNil(t, args.Geometry) {
+		assert.Equal(t, "Polygon", args.Geometry.Type)
+	}
+
+	args, err = NewCreateLocationArgs("route", Polyline{{0, 0}, {1, 1}})
+	require.NoError(t, err)
+	if assert.NotNil(t, args.Geometry) {
+		assert.Equal(t, "LineString", args.Geometry.Type)
+	}
+
+	args, err = NewCreateLocationArgs("home", LatLon{Lat: 42.3, Lon: -71.1})
+	require.NoError(t, err)
+	assert.Equal(t, &LatLon{Lat: 42.3, Lon: -71.1}, args.Point)
+
+	_, err = NewCreateLocationArgs("field", Polygon{square[:4]})
+	assert.Error(t, err)
+	_, err = NewCreateLocationArgs("saved", LocationID("abc"))
+	assert.Error(t, err)
+}
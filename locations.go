@@ -0,0 +1,255 @@
+package climacell
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EndpointLocations is the ClimaCell API's endpoint for managing saved
+// locations. Requests for a single location go to locations/{id}, but are
+// labeled with EndpointLocations in logs, metrics, and traces.
+const EndpointLocations Endpoint = "locations"
+
+// SavedLocation is a location saved to a ClimaCell account, which weather data
+// can be requested for by its ID.
+type SavedLocation struct {
+	// ID is the location's ID, which can be used as a LocationID.
+	ID string `json:"id"`
+	// Name is the location's name.
+	Name string `json:"name"`
+	// Point, if the location is a single point, contains its coordinates.
+	Point *LatLon `json:"point,omitempty"`
+	// Geometry, if the location is an area, such as a polygon, contains its
+	// shape in GeoJSON.
+	Geometry *Geometry `json:"geometry,omitempty"`
+	// CreatedAt and UpdatedAt are when the location was created and last
+	// updated, if the API returned them.
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// LocationID returns the LocationID for requesting weather data for this
+// location.
+func (l SavedLocation) LocationID() LocationID { return LocationID(l.ID) }
+
+// CreateLocationArgs are the arguments for creating a saved location. Exactly
+// one of Point and Geometry should be set.
+type CreateLocationArgs struct {
+	// Name is the name of the location.
+	Name string `json:"name"`
+	// Point is the coordinates of the location, for point locations.
+	Point *LatLon `json:"point,omitempty"`
+	// Geometry is the shape of the location, for locations that are areas.
+	Geometry *Geometry `json:"geometry,omitempty"`
+}
+
+// NewCreateLocationArgs returns the CreateLocationArgs for saving a Location
+// under a name. LatLons are saved as points, while Polygons and Polylines are
+// validated, then saved as geometries. LocationIDs cannot be saved, since they
+// are already saved locations.
+func NewCreateLocationArgs(name string, loc Location) (CreateLocationArgs, error) {
+	args := CreateLocationArgs{Name: name}
+	switch l := loc.(type) {
+	case LatLon:
+		args.Point = &l
+	case *LatLon:
+		args.Point = l
+	case Polygon:
+		if err := l.Validate(); err != nil {
+			return CreateLocationArgs{}, errors.WithMessage(err, "invalid polygon")
+		}
+		args.Geometry = l.Geometry()
+	case Polyline:
+		if err := l.Validate(); err != nil {
+			return CreateLocationArgs{}, errors.WithMessage(err, "invalid polyline")
+		}
+		args.Geometry = l.Geometry()
+	default:
+		return CreateLocationArgs{}, errors.Errorf("cannot save a location of type %T", loc)
+	}
+	return args, nil
+}
+
+// UpdateLocationArgs are the arguments for updating a saved location. Only
+// non-nil fields are updated.
+type UpdateLocationArgs struct {
+	// Name, if non-nil, is the location's new name.
+	Name *string `json:"name,omitempty"`
+	// Point, if non-nil, is the location's new coordinates.
+	Point *LatLon `json:"point,omitempty"`
+	// Geometry, if non-nil, is the location's new shape.
+	Geometry *Geometry `json:"geometry,omitempty"`
+}
+
+// ListLocationsArgs are the pagination arguments for listing saved
+// locations.
+type ListLocationsArgs struct {
+	// Page, if nonzero, is the page of locations to retrieve, starting at
+	// 1, filling in the "page" query parameter.
+	Page int
+	// PerPage, if nonzero, is the number of locations per page, filling in
+	// the "per_page" query parameter.
+	PerPage int
+}
+
+// QueryParams converts a ListLocationsArgs to query parameters.
+func (args ListLocationsArgs) QueryParams() url.Values {
+	return pageQueryParams(args.Page, args.PerPage)
+}
+
+// pageQueryParams returns the query parameters for a page of a paginated
+// list endpoint.
+func pageQueryParams(page, perPage int) url.Values {
+	q := make(url.Values)
+	if page > 0 {
+		q.Add("page", strconv.Itoa(page))
+	}
+	if perPage > 0 {
+		q.Add("per_page", strconv.Itoa(perPage))
+	}
+	return q
+}
+
+// CreateLocation saves a new location on successful requests to the
+// /locations endpoint, returning the created SavedLocation, or an
+// ErrorResponse on a 400, 401, 403, or 500 error.
+func (c *Client) CreateLocation(ctx context.Context, args CreateLocationArgs) (SavedLocation, error) {
+	if (args.Point == nil) == (args.Geometry == nil) {
+		return SavedLocation{}, errors.New("exactly one of Point and Geometry must be set to create a location")
+	}
+
+	var l SavedLocation
+	err := c.call(ctx, &apiRequest{
+		method: http.MethodPost,
+		endpt:  EndpointLocations,
+		path:   string(EndpointLocations),
+		body:   args,
+	}, &l)
+	if err != nil {
+		return SavedLocation{}, err
+	}
+	return l, nil
+}
+
+// ListLocations returns a page of the account's saved locations on
+// successful requests to the /locations endpoint, or an ErrorResponse on a
+// 400, 401, 403, or 500 error. If fewer locations than the page size are
+// returned, that is the last page.
+func (c *Client) ListLocations(ctx context.Context, args ListLocationsArgs) ([]SavedLocation, error) {
+	var l []SavedLocation
+	err := c.call(ctx, &apiRequest{
+		method: http.MethodGet,
+		endpt:  EndpointLocations,
+		path:   string(EndpointLocations),
+		query:  args.QueryParams(),
+	}, &l)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// ListAllLocations returns all of the account's saved locations, retrieving
+// them perPage at a time with ListLocations.
+func (c *Client) ListAllLocations(ctx context.Context, perPage int) ([]SavedLocation, error) {
+	if perPage <= 0 {
+		return nil, errors.New("perPage must be positive")
+	}
+
+	var all []SavedLocation
+	for page := 1; ; page++ {
+		l, err := c.ListLocations(ctx, ListLocationsArgs{Page: page, PerPage: perPage})
+		if err != nil {
+			return nil, errors.WithMessagef(err, "listing page %d of locations", page)
+		}
+		all = append(all, l...)
+		if len(l) < perPage {
+			return all, nil
+		}
+	}
+}
+
+// GetLocation returns a saved location on successful requests to the
+// /locations/{id} endpoint, or an ErrorResponse on a 400, 401, 403, 404, or
+// 500 error.
+func (c *Client) GetLocation(ctx context.Context, id LocationID) (SavedLocation, error) {
+	path, err := locationPath(id)
+	if err != nil {
+		return SavedLocation{}, err
+	}
+
+	var l SavedLocation
+	err = c.call(ctx, &apiRequest{
+		method: http.MethodGet,
+		endpt:  EndpointLocations,
+		path:   path,
+	}, &l)
+	if err != nil {
+		return SavedLocation{}, err
+	}
+	return l, nil
+}
+
+// UpdateLocation updates a saved location on successful requests to the
+// /locations/{id} endpoint, returning the updated SavedLocation, or an
+// ErrorResponse on a 400, 401, 403, 404, or 500 error.
+func (c *Client) UpdateLocation(
+	ctx context.Context,
+	id LocationID,
+	args UpdateLocationArgs,
+) (SavedLocation, error) {
+	path, err := locationPath(id)
+	if err != nil {
+		return SavedLocation{}, err
+	}
+
+	var l SavedLocation
+	err = c.call(ctx, &apiRequest{
+		method: http.MethodPatch,
+		endpt:  EndpointLocations,
+		path:   path,
+		body:   args,
+	}, &l)
+	if err != nil {
+		return SavedLocation{}, err
+	}
+	return l, nil
+}
+
+// DeleteLocation deletes a saved location on successful requests to the
+// /locations/{id} endpoint, or returns an ErrorResponse on a 400, 401, 403,
+// 404, or 500 error.
+func (c *Client) DeleteLocation(ctx context.Context, id LocationID) error {
+	path, err := locationPath(id)
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, &apiRequest{
+		method: http.MethodDelete,
+		endpt:  EndpointLocations,
+		path:   path,
+	}, nil)
+}
+
+func locationPath(id LocationID) (string, error) {
+	return resourcePath(EndpointLocations, string(id))
+}
+
+// resourcePath returns the path to a single resource under an endpoint, such
+// as locations/{id}, with the ID path-escaped. Blank IDs are rejected, since
+// their path is the endpoint's collection, as are "." and "..", since they
+// would resolve to a different endpoint.
+func resourcePath(endpt Endpoint, id string) (string, error) {
+	switch id {
+	case "":
+		return "", errors.Errorf("an ID is required for %s requests", endpt)
+	case ".", "..":
+		return "", errors.Errorf("invalid ID %q for %s requests", id, endpt)
+	}
+	return string(endpt) + "/" + url.PathEscape(id), nil
+}
@@ -0,0 +1,165 @@
+package climacell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// locationsServer is a mock of the /locations endpoints that keeps saved
+// locations in memory.
+func locationsServer(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+	saved := make(map[string]SavedLocation)
+	var order []string
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/locations"), "/")
+		switch {
+		case r.Method == http.MethodPost && id == "":
+			var args CreateLocationArgs
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&args))
+			l := SavedLocation{
+				ID:       strconv.Itoa(len(order) + 1),
+				Name:     args.Name,
+				Point:    args.Point,
+				Geometry: args.Geometry,
+			}
+			saved[l.ID] = l
+			order = append(order, l.ID)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(l)
+		case r.Method == http.MethodGet && id == "":
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+			l := []SavedLocation{}
+			for i := (page - 1) * perPage; i < page*perPage && i < len(order); i++ {
+				if loc, ok := saved[order[i]]; ok {
+					l = append(l, loc)
+				}
+			}
+			json.NewEncoder(w).Encode(l)
+		case saved[id].ID == "":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"statusCode": 404, "errorCode": "NotFound", "message": "location not found"}`)
+		case r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(saved[id])
+		case r.Method == http.MethodPatch:
+			var args UpdateLocationArgs
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&args))
+			l := saved[id]
+			if args.Name != nil {
+				l.Name = *args.Name
+			}
+			saved[id] = l
+			json.NewEncoder(w).Encode(l)
+		case r.Method == http.MethodDelete:
+			delete(saved, id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestLocationsLifecycle(t *testing.T) {
+	server := locationsServer(t)
+	defer server.Close()
+
+	client := NewClient("test_api_key", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	store, err := client.CreateLocation(ctx, CreateLocationArgs{
+		Name:  "Store #1",
+		Point: &LatLon{Lat: 42.3826, Lon: -71.146},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1", store.ID)
+	assert.Equal(t, LocationID("1"), store.LocationID())
+
+	field, err := client.CreateLocation(ctx, CreateLocationArgs{
+		Name: "Field",
+		Geometry: PolygonGeometry([]LatLon{
+			{Lat: 0, Lon: 0}, {Lat: 0, Lon: 1}, {Lat: 1, Lon: 1},
+		}),
+	})
+	require.NoError(t, err)
+	if assert.NotNil(t, field.Geometry) {
+		assert.Equal(t, "Polygon", field.Geometry.Type)
+		assert.JSONEq(t, `[[[0,0],[1,0],[1,1],[0,0]]]`, string(field.Geometry.Coordinates))
+	}
+
+	_, err = client.CreateLocation(ctx, CreateLocationArgs{Name: "Nowhere"})
+	assert.Error(t, err)
+
+	newName := "Store #1 (renamed)"
+	updated, err := client.UpdateLocation(ctx, store.LocationID(), UpdateLocationArgs{Name: &newName})
+	require.NoError(t, err)
+	assert.Equal(t, newName, updated.Name)
+
+	got, err := client.GetLocation(ctx, store.LocationID())
+	require.NoError(t, err)
+	assert.Equal(t, newName, got.Name)
+
+	all, err := client.ListAllLocations(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	require.NoError(t, client.DeleteLocation(ctx, store.LocationID()))
+	_, err = client.GetLocation(ctx, store.LocationID())
+	if errRes, ok := err.(*ErrorResponse); assert.True(t, ok, "expected an ErrorResponse, got %v", err) {
+		assert.Equal(t, 404, errRes.StatusCode)
+	}
+}
+
+func TestCreateLocationNotRetried(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient("test_api_key",
+		WithBaseURL(server.URL),
+		WithRetryPolicy(&RetryPolicy{MaxAttempts: 3}),
+	)
+	_, err := client.CreateLocation(context.Background(), CreateLocationArgs{
+		Name:  "Store #1",
+		Point: &LatLon{Lat: 42.3826, Lon: -71.146},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestLocationIDsEscaped(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient("test_api_key", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	assert.Error(t, client.DeleteLocation(ctx, ""))
+	_, err := client.GetLocation(ctx, "..")
+	assert.Error(t, err)
+	assert.Empty(t, paths, "requests with invalid IDs shouldn't be sent")
+
+	require.NoError(t, client.DeleteLocation(ctx, "../weather/realtime"))
+	assert.Equal(t, []string{"/locations/..%2Fweather%2Frealtime"}, paths)
+}
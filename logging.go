@@ -0,0 +1,144 @@
+package climacell
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Logger is the interface a Client logs through. The standard library's
+// *log.Logger implements it.
+//
+// Each HTTP request the Client sends is logged as a single line of key=value
+// pairs. To get the fields of each request as a RequestLog instead, such as
+// for passing them to a structured logging library, the Logger can also
+// implement RequestLogger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// RequestLogger is implemented by Loggers that take in structured logs of
+// the requests a Client sends. If a Client's Logger implements
+// RequestLogger, LogRequest is called for each request instead of Printf.
+type RequestLogger interface {
+	LogRequest(l RequestLog)
+}
+
+// RequestLog describes a single HTTP request a Client sent to the ClimaCell
+// API. Retries of a request are logged as separate requests.
+type RequestLog struct {
+	// Endpoint is the endpoint the request was sent to.
+	Endpoint Endpoint
+	// Method is the request's HTTP method.
+	Method string
+	// URL is the request's URL, with any apikey query parameter redacted.
+	URL string
+	// Query contains the request's query parameters, with any apikey query
+	// parameter redacted.
+	Query url.Values
+	// StatusCode is the response's HTTP status code, or 0 if no response
+	// was received.
+	StatusCode int
+	// Latency is how long it took from sending the request to finishing
+	// reading the response.
+	Latency time.Duration
+	// BytesSent is the size of the request body.
+	BytesSent int64
+	// BytesReceived is the number of bytes read from the response body.
+	BytesReceived int64
+	// Err is the error the request failed with, if any.
+	Err error
+}
+
+// String formats the RequestLog as key=value pairs.
+func (l RequestLog) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "endpoint=%s method=%s url=%q status=%d latency=%s bytes_sent=%d bytes_received=%d",
+		l.Endpoint, l.Method, l.URL, l.StatusCode, l.Latency, l.BytesSent, l.BytesReceived)
+	if l.Err != nil {
+		fmt.Fprintf(&b, " error=%q", l.Err.Error())
+	}
+	return b.String()
+}
+
+// redacted is what sensitive values are replaced with in logs.
+const redacted = "REDACTED"
+
+// sensitiveParams are query parameters whose values are never logged. The
+// apikey header is never logged either, since headers aren't logged at all.
+var sensitiveParams = []string{"apikey"}
+
+func (c *Client) logf(format string, v ...interface{}) {
+	if c.logger != nil {
+		c.logger.Printf(format, v...)
+	}
+}
+
+func (c *Client) logRequest(l RequestLog) {
+	switch logger := c.logger.(type) {
+	case nil:
+	case RequestLogger:
+		logger.LogRequest(l)
+	default:
+		logger.Printf("climacell request: %s", l)
+	}
+}
+
+// newRequestLog makes the RequestLog for an HTTP request. res is nil if the
+// request failed before getting a response.
+func newRequestLog(
+	req *http.Request,
+	endpt Endpoint,
+	res *http.Response,
+	latency time.Duration,
+	bytesReceived int64,
+	err error,
+) RequestLog {
+	u := *req.URL
+	query := redactQuery(u.Query())
+	u.RawQuery = query.Encode()
+
+	l := RequestLog{
+		Endpoint:      endpt,
+		Method:        req.Method,
+		URL:           u.String(),
+		Query:         query,
+		Latency:       latency,
+		BytesReceived: bytesReceived,
+		Err:           err,
+	}
+	if req.ContentLength > 0 {
+		l.BytesSent = req.ContentLength
+	}
+	if res != nil {
+		l.StatusCode = res.StatusCode
+	}
+	return l
+}
+
+// redactQuery replaces the values of sensitive query parameters.
+func redactQuery(q url.Values) url.Values {
+	for k := range q {
+		for _, param := range sensitiveParams {
+			if strings.EqualFold(k, param) {
+				q[k] = []string{redacted}
+			}
+		}
+	}
+	return q
+}
+
+// countingReadCloser counts the bytes read from an io.ReadCloser.
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,72 @@
+package climacell
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct{ logs []RequestLog }
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {}
+
+func (l *recordingLogger) LogRequest(rl RequestLog) { l.logs = append(l.logs, rl) }
+
+func TestLoggingRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"statusCode": 400, "errorCode": "BadRequest", "message": "invalid fields"}`)
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client := NewClient("test_api_key", WithBaseURL(server.URL), WithLogger(logger))
+	_, err := client.HourlyForecast(ForecastArgs{
+		Location: LatLon{Lat: 11.3, Lon: 52.4},
+		Fields:   []string{"temp"},
+	})
+	require.Error(t, err)
+
+	require.Len(t, logger.logs, 1)
+	l := logger.logs[0]
+	assert.Equal(t, EndpointHourlyForecast, l.Endpoint)
+	assert.Equal(t, http.MethodGet, l.Method)
+	assert.Equal(t, "temp", l.Query.Get("fields"))
+	assert.Equal(t, http.StatusBadRequest, l.StatusCode)
+	assert.True(t, l.BytesReceived > 0)
+	assert.True(t, l.Latency > 0)
+	assert.Equal(t, err, l.Err)
+}
+
+func TestLoggingWithPrintf(t *testing.T) {
+	server, _ := flakyServer(0, 0, nil)
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient("test_api_key",
+		WithBaseURL(server.URL),
+		WithLogger(log.New(&buf, "", 0)),
+	)
+	_, err := client.Nowcast(ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}})
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, "endpoint=weather/nowcast")
+	assert.Contains(t, out, "status=200")
+	assert.Contains(t, out, "bytes_received=2")
+	assert.NotContains(t, out, "test_api_key")
+}
+
+func TestRedactQuery(t *testing.T) {
+	q := redactQuery(map[string][]string{
+		"apikey": {"test_api_key"},
+		"fields": {"temp"},
+	})
+	assert.Equal(t, "apikey=REDACTED&fields=temp", q.Encode())
+}
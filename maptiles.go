@@ -0,0 +1,153 @@
+package climacell
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EndpointMapTile is v4 of the ClimaCell API's endpoint for weather map
+// tiles. Requests for tiles go to paths under map/tile/{z}/{x}/{y}, but are
+// labeled with EndpointMapTile in logs, metrics, and traces.
+const EndpointMapTile Endpoint = "map/tile"
+
+// MaxTileZoom is the highest zoom level map tiles can be requested at.
+const MaxTileZoom = 22
+
+// maxMercatorLat is the latitude where the Web Mercator projection map tiles
+// use is cut off, so the world map is a square.
+const maxMercatorLat = 85.0511287798066
+
+// MapTile returns the PNG image of a weather map tile, along with its content
+// type, on successful requests to the /map/tile/{z}/{x}/{y}/{field}/{time}.png
+// endpoint, or an ErrorResponse on a 400, 401, 403, 404, or 500 error. field is
+// the v4 field the tile shows, such as "precipitationIntensity", and z, x, and
+// y are the tile's zoom level and Web Mercator tile indices, which you can get
+// for a location with TileAt. If t is zero, the tile for the current time is
+// returned.
+func (c *Client) MapTile(
+	ctx context.Context,
+	field string,
+	z, x, y int,
+	t time.Time,
+) (png []byte, contentType string, err error) {
+	switch {
+	case field == "":
+		return nil, "", errors.New("a field is required for map tile requests")
+	case field == ".", field == "..", strings.Contains(field, "/"):
+		// these would change which endpoint the request is sent to
+		return nil, "", errors.Errorf("invalid field %q for map tile requests", field)
+	}
+	if err := (Tile{Z: z, X: x, Y: y}).validate(); err != nil {
+		return nil, "", err
+	}
+
+	timestamp := "now"
+	if !t.IsZero() {
+		timestamp = t.UTC().Format(time.RFC3339)
+	}
+
+	var res rawResponse
+	err = c.call(ctx, &apiRequest{
+		method: http.MethodGet,
+		endpt:  EndpointMapTile,
+		path: fmt.Sprintf("%s/%d/%d/%d/%s/%s.png",
+			EndpointMapTile, z, x, y, url.PathEscape(field), timestamp),
+		header: http.Header{"Accept": []string{"image/png"}},
+		v4:     true,
+	}, &res)
+	if err != nil {
+		return nil, "", err
+	}
+	return res.body, res.contentType, nil
+}
+
+// Tile is the zoom level and Web Mercator indices of a map tile. At zoom level
+// Z, the world map is split into 2^Z by 2^Z tiles, with X increasing eastward
+// from longitude -180, and Y increasing southward from the top of the map.
+type Tile struct {
+	Z, X, Y int
+}
+
+func (t Tile) validate() error {
+	if t.Z < 0 || t.Z > MaxTileZoom {
+		return errors.Errorf("zoom level %d is not between 0 and %d", t.Z, MaxTileZoom)
+	}
+	n := 1 << uint(t.Z)
+	if t.X < 0 || t.X >= n || t.Y < 0 || t.Y >= n {
+		return errors.Errorf("tile %d/%d is out of bounds at zoom level %d", t.X, t.Y, t.Z)
+	}
+	return nil
+}
+
+// TileAt returns the map tile containing a location at zoom level z. Latitudes
+// beyond the Web Mercator projection's cutoff of about ±85.05 degrees are in
+// the tiles at the top or bottom of the map.
+func TileAt(loc LatLon, z int) Tile {
+	n := float64(int(1) << uint(z))
+	lat := math.Max(-maxMercatorLat, math.Min(maxMercatorLat, loc.Lat))
+	latRad := lat * math.Pi / 180
+
+	x := (loc.Lon + 180) / 360 * n
+	y := (1 - math.Log(math.Tan(latRad)+1/math.Cos(latRad))/math.Pi) / 2 * n
+	return Tile{Z: z, X: clampTileIndex(x, n), Y: clampTileIndex(y, n)}
+}
+
+func clampTileIndex(i, n float64) int {
+	return int(math.Max(0, math.Min(n-1, math.Floor(i))))
+}
+
+// TileRange is a rectangle of map tiles at a single zoom level, from MinX to
+// MaxX and MinY to MaxY inclusive. If MinX is greater than MaxX, the range
+// crosses the antimeridian, so it wraps around from the eastern edge of the
+// map to the western edge.
+type TileRange struct {
+	Z          int
+	MinX, MaxX int
+	MinY, MaxY int
+}
+
+// TilesInBounds returns the range of map tiles at zoom level z that cover the
+// bounding box with the corners southWest and northEast. If southWest's
+// longitude is greater than northEast's, the bounding box crosses the
+// antimeridian.
+func TilesInBounds(southWest, northEast LatLon, z int) TileRange {
+	// tile Y indices increase southward, so the northwest corner has the
+	// minimum indices
+	nw := TileAt(LatLon{Lat: northEast.Lat, Lon: southWest.Lon}, z)
+	se := TileAt(LatLon{Lat: southWest.Lat, Lon: northEast.Lon}, z)
+	return TileRange{Z: z, MinX: nw.X, MaxX: se.X, MinY: nw.Y, MaxY: se.Y}
+}
+
+// Len returns the number of tiles in the range.
+func (r TileRange) Len() int {
+	if r.MaxY < r.MinY {
+		return 0
+	}
+	return r.width() * (r.MaxY - r.MinY + 1)
+}
+
+func (r TileRange) width() int {
+	if r.MinX > r.MaxX {
+		return (1 << uint(r.Z)) - r.MinX + r.MaxX + 1
+	}
+	return r.MaxX - r.MinX + 1
+}
+
+// Tiles returns each tile in the range, row by row from the northwest corner.
+func (r TileRange) Tiles() []Tile {
+	tiles := make([]Tile, 0, r.Len())
+	n := 1 << uint(r.Z)
+	for y := r.MinY; y <= r.MaxY; y++ {
+		for i := 0; i < r.width(); i++ {
+			tiles = append(tiles, Tile{Z: r.Z, X: (r.MinX + i) % n, Y: y})
+		}
+	}
+	return tiles
+}
@@ -0,0 +1,114 @@
+package climacell
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapTile(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\nfake tile")
+	var path, accept string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, accept = r.URL.Path, r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(png)
+	}))
+	defer srv.Close()
+
+	c := NewClient("test_api_key", WithV4BaseURL(srv.URL))
+	at := time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+	b, contentType, err := c.MapTile(context.Background(), "precipitationIntensity", 5, 9, 11, at)
+	require.NoError(t, err)
+	assert.Equal(t, png, b)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, "/map/tile/5/9/11/precipitationIntensity/2020-09-01T12:00:00Z.png", path)
+	assert.Equal(t, "image/png", accept)
+
+	_, _, err = c.MapTile(context.Background(), "temperature", 1, 0, 0, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "/map/tile/1/0/0/temperature/now.png", path)
+}
+
+func TestMapTileErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code": 404001, "type": "Not Found", "message": "no such field"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("test_api_key", WithV4BaseURL(srv.URL))
+	_, _, err := c.MapTile(context.Background(), "notAField", 0, 0, 0, time.Time{})
+	assert.Equal(t, &ErrorResponse{
+		StatusCode: 404,
+		ErrorCode:  "404001",
+		Message:    "no such field",
+	}, err)
+
+	// invalid tiles are caught before sending a request
+	_, _, err = c.MapTile(context.Background(), "temperature", 2, 4, 0, time.Time{})
+	assert.Error(t, err)
+	_, _, err = c.MapTile(context.Background(), "temperature", MaxTileZoom+1, 0, 0, time.Time{})
+	assert.Error(t, err)
+	_, _, err = c.MapTile(context.Background(), "", 0, 0, 0, time.Time{})
+	assert.Error(t, err)
+}
+
+func TestMapTileFieldEscaped(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("\x89PNG"))
+	}))
+	defer srv.Close()
+
+	c := NewClient("test_api_key", WithV4BaseURL(srv.URL))
+	ctx := context.Background()
+	for _, field := range []string{".", "..", "../../locations?x=", "a/b"} {
+		_, _, err := c.MapTile(ctx, field, 1, 0, 0, time.Time{})
+		assert.Error(t, err, field)
+	}
+	assert.Empty(t, paths, "requests with invalid fields shouldn't be sent")
+
+	_, _, err := c.MapTile(ctx, "temp?x=1#y", 1, 0, 0, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/map/tile/1/0/0/temp%3Fx=1%23y/now.png"}, paths)
+}
+
+func TestTileAt(t *testing.T) {
+	tests := []struct {
+		name string
+		loc  LatLon
+		z    int
+		want Tile
+	}{
+		{name: "zoom 0", loc: LatLon{Lat: 42.3, Lon: -71.1}, z: 0, want: Tile{0, 0, 0}},
+		{name: "Boston", loc: LatLon{Lat: 42.3601, Lon: -71.0589}, z: 10, want: Tile{10, 309, 378}},
+		{name: "southeast", loc: LatLon{Lat: -33.9, Lon: 151.2}, z: 1, want: Tile{1, 1, 1}},
+		{name: "north pole", loc: LatLon{Lat: 90, Lon: 0}, z: 3, want: Tile{3, 4, 0}},
+		{name: "antimeridian", loc: LatLon{Lat: -90, Lon: 180}, z: 3, want: Tile{3, 7, 7}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, TileAt(tc.loc, tc.z))
+		})
+	}
+}
+
+func TestTilesInBounds(t *testing.T) {
+	r := TilesInBounds(LatLon{Lat: -10, Lon: -10}, LatLon{Lat: 10, Lon: 10}, 2)
+	assert.Equal(t, TileRange{Z: 2, MinX: 1, MaxX: 2, MinY: 1, MaxY: 2}, r)
+	assert.Equal(t, 4, r.Len())
+	assert.Equal(t, []Tile{{2, 1, 1}, {2, 2, 1}, {2, 1, 2}, {2, 2, 2}}, r.Tiles())
+
+	// bounding boxes crossing the antimeridian wrap around
+	r = TilesInBounds(LatLon{Lat: 10, Lon: 170}, LatLon{Lat: 20, Lon: -170}, 2)
+	assert.Equal(t, TileRange{Z: 2, MinX: 3, MaxX: 0, MinY: 1, MaxY: 1}, r)
+	assert.Equal(t, []Tile{{2, 3, 1}, {2, 0, 1}}, r.Tiles())
+}
@@ -0,0 +1,31 @@
+package climacell
+
+import "time"
+
+// Metrics records metrics on the requests a Client sends, such as for
+// exporting to a monitoring system. PrometheusMetrics is an implementation
+// that serves its metrics in the Prometheus text format. Implementations must
+// be safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest is called after each HTTP request sent to the API,
+	// including retries, with the response's status code, or 0 if no
+	// response was received, and how long the request took.
+	ObserveRequest(endpt Endpoint, statusCode int, latency time.Duration)
+	// IncRetries is called each time a request is retried.
+	IncRetries(endpt Endpoint)
+	// IncCacheHits is called each time a response is served from the
+	// Client's cache.
+	IncCacheHits(endpt Endpoint)
+	// IncCacheMisses is called each time a response for a cached endpoint
+	// was not in the Client's cache.
+	IncCacheMisses(endpt Endpoint)
+	// IncDecodeErrors is called each time a response could not be
+	// deserialized.
+	IncDecodeErrors(endpt Endpoint)
+}
+
+// WithMetrics sets where the Client records metrics on its requests. By
+// default, no metrics are recorded.
+func WithMetrics(m Metrics) Option {
+	return func(c *Client) { c.metrics = m }
+}
@@ -0,0 +1,80 @@
+package climacell
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Request is a request for weather data passing through a Client's
+// middleware.
+type Request struct {
+	// Endpoint is the endpoint the request is for.
+	Endpoint Endpoint
+	// Args are the arguments the request is being sent with, including
+	// the Client's default unit system and fields. Middleware can change
+	// them before passing the Request on.
+	Args ForecastArgs
+	// Header contains extra headers to send the request with. The apikey
+	// header is not in Header; it is added right before the request is
+	// sent, so middleware never sees the API key.
+	Header http.Header
+
+	baseURL string
+}
+
+// URL returns the URL the request is sent to, including its query parameters.
+// Since the API key is sent in a header, the URL is safe to log.
+func (r *Request) URL() string {
+	u, err := url.Parse(r.baseURL)
+	if err != nil {
+		return r.baseURL + string(r.Endpoint) + "?" + r.Args.QueryParams().Encode()
+	}
+	u = u.ResolveReference(&url.URL{Path: string(r.Endpoint)})
+	u.RawQuery = r.Args.QueryParams().Encode()
+	return u.String()
+}
+
+// Handler handles a Request for weather data, deserializing the weather data
+// into result, which is a pointer to the type returned by the Client method
+// for the request's endpoint, such as *[]HourlyForecast for an hourly
+// forecast, or *RealTime for real-time weather data.
+type Handler func(ctx context.Context, req *Request, result interface{}) error
+
+// Middleware wraps the Handler a Client sends its requests for weather data
+// through. Middleware can change a Request before calling next, and after
+// calling next, can look at or change the deserialized result or the error.
+//
+// For example, a Middleware that logs failed requests would look like:
+//
+//	func logErrors(next climacell.Handler) climacell.Handler {
+//		return func(ctx context.Context, req *climacell.Request, result interface{}) error {
+//			err := next(ctx, req, result)
+//			if err != nil {
+//				log.Printf("request to %s failed: %v", req.URL(), err)
+//			}
+//			return err
+//		}
+//	}
+type Middleware func(next Handler) Handler
+
+// WithMiddleware adds Middleware to the chain the Client sends its requests
+// for weather data through. The first Middleware added is the outermost, so
+// it sees requests first, and results last.
+//
+// Middleware wraps the whole request, including getting responses from the
+// cache and retrying failed requests, so each Middleware is called once per
+// Client method call. Streamed requests, such as from NowcastStream, don't go
+// through the Client's middleware.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) { c.middleware = append(c.middleware, mw...) }
+}
+
+// chainMiddleware wraps h in the given middleware, with the first Middleware
+// being the outermost.
+func chainMiddleware(h Handler, mw []Middleware) Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
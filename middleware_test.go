@@ -0,0 +1,68 @@
+package climacell
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewareChain(t *testing.T) {
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"temp": {"value": 10, "units": "C"}}]`))
+	}))
+	defer server.Close()
+
+	var calls []string
+	var loggedURL string
+	outer := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request, result interface{}) error {
+			calls = append(calls, "outer")
+			loggedURL = req.URL()
+			req.Header.Set("X-Request-Id", "abc123")
+			return next(ctx, req, result)
+		}
+	}
+	inner := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request, result interface{}) error {
+			calls = append(calls, "inner")
+			assert.Equal(t, EndpointHourlyForecast, req.Endpoint)
+			assert.Equal(t, "us", req.Args.UnitSystem)
+			if err := next(ctx, req, result); err != nil {
+				return err
+			}
+
+			// double every temperature
+			samples := result.(*[]HourlyForecast)
+			for _, s := range *samples {
+				*s.Temp.Value *= 2
+			}
+			return nil
+		}
+	}
+
+	client := NewClient("test_api_key",
+		WithBaseURL(server.URL),
+		WithUnitSystem("us"),
+		WithMiddleware(outer, inner),
+	)
+	forecast, err := client.HourlyForecast(ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}})
+	require.NoError(t, err)
+	require.Len(t, forecast, 1)
+
+	if temp, ok := forecast[0].Temp.GetValue(); assert.True(t, ok) {
+		assert.EqualValues(t, 20, temp)
+	}
+	assert.Equal(t, []string{"outer", "inner"}, calls)
+	assert.Equal(t, "abc123", gotHeader.Get("X-Request-Id"))
+	assert.Equal(t, "test_api_key", gotHeader.Get("apikey"))
+	assert.True(t, strings.HasPrefix(loggedURL, server.URL+"/weather/forecast/hourly?"), loggedURL)
+	assert.NotContains(t, loggedURL, "test_api_key")
+}
@@ -0,0 +1,108 @@
+package climacell
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Option configures a Client made with NewClient.
+type Option func(*Client)
+
+// NewClient takes in a ClimaCell API key and any number of Options, and
+// returns a client for the ClimaCell API. With no Options, the Client is the
+// same as one returned by New.
+// WARNING: DO NOT share your API key with anyone; if someone else gains access
+// to it, they can make requests to the API under your identity. Because of
+// this, it is ill-advised to have the key directly in your source code.
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:   defaultBaseURL,
+		v4BaseURL: defaultV4BaseURL,
+		apiKey:    apiKey,
+		c:         newDefaultHTTPClient(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithBaseURL sets the base URL the Client sends requests to, such as the URL
+// of a staging environment, a proxy, or a mock API server. The default is
+// https://api.climacell.co/v3/.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		// Endpoint paths are resolved relative to the base URL, so without
+		// a trailing slash, the last path segment would get dropped.
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+		c.baseURL = baseURL
+	}
+}
+
+// WithV4BaseURL sets the base URL the Client sends requests to v4 of the API
+// to, such as for the Timelines method. The default is
+// https://data.climacell.co/v4/.
+func WithV4BaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+		c.v4BaseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the net/http Client the Client sends requests with. The
+// default is a net/http Client where requests time out after a minute
+// without a response.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.c = httpClient }
+}
+
+// WithUserAgent sets the User-Agent header the Client sends requests with.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// WithUnitSystem sets the unit system, "si" or "us", the Client requests
+// weather data in when a ForecastArgs does not have a UnitSystem.
+func WithUnitSystem(unitSystem string) Option {
+	return func(c *Client) { c.unitSystem = unitSystem }
+}
+
+// WithFields sets the fields the Client requests when a ForecastArgs does not
+// have any Fields.
+func WithFields(fields ...string) Option {
+	return func(c *Client) { c.fields = append([]string(nil), fields...) }
+}
+
+// WithRetryPolicy sets the policy the Client uses for retrying failed
+// requests. By default, failed requests are not retried.
+func WithRetryPolicy(p *RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// WithRateLimiter sets the RateLimiter the Client waits on before sending each
+// request. By default, requests are not rate limited.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(c *Client) { c.limiter = l }
+}
+
+// WithLogger sets the Logger the Client logs to. By default, nothing is
+// logged.
+func WithLogger(l Logger) Option {
+	return func(c *Client) { c.logger = l }
+}
+
+// withDefaults fills in the Client's default unit system and fields on a
+// ForecastArgs that doesn't have them.
+func (c *Client) withDefaults(args ForecastArgs) ForecastArgs {
+	if args.UnitSystem == "" {
+		args.UnitSystem = c.unitSystem
+	}
+	if len(args.Fields) == 0 {
+		args.Fields = c.fields
+	}
+	return args
+}
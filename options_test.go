@@ -0,0 +1,53 @@
+package climacell
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test_api_key",
+		WithBaseURL(server.URL+"/v3"),
+		WithUserAgent("climacell-go-test"),
+		WithUnitSystem("us"),
+		WithFields("temp", "humidity"),
+	)
+
+	_, err := client.HourlyForecast(ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}})
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "/v3/weather/forecast/hourly", got.URL.Path)
+	assert.Equal(t, "climacell-go-test", got.Header.Get("User-Agent"))
+	assert.Equal(t, "test_api_key", got.Header.Get("apikey"))
+	assert.Equal(t, "us", got.URL.Query().Get("unit_system"))
+	assert.Equal(t, "temp,humidity", got.URL.Query().Get("fields"))
+
+	_, err = client.HourlyForecast(ForecastArgs{
+		Location:   LatLon{Lat: 11.3, Lon: 52.4},
+		UnitSystem: "si",
+		Fields:     []string{"wind_speed"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "si", got.URL.Query().Get("unit_system"))
+	assert.Equal(t, "wind_speed", got.URL.Query().Get("fields"))
+}
+
+func TestNewClientDefaults(t *testing.T) {
+	c := NewClient("test_api_key")
+	assert.Equal(t, defaultBaseURL, c.baseURL)
+	assert.NotNil(t, c.c)
+	assert.Nil(t, c.retry)
+	assert.Nil(t, c.limiter)
+}
@@ -0,0 +1,214 @@
+package climacell
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the buckets in
+// PrometheusMetrics' request latency histogram.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// PrometheusMetrics is a Metrics implementation that keeps its metrics in
+// memory, and serves them over HTTP in the Prometheus text exposition format,
+// so they can be scraped without any other dependencies. The metrics it
+// serves are:
+//
+//   - climacell_requests_total: counter of requests, by endpoint and status
+//     code, where a code of "error" means no response was received
+//   - climacell_request_duration_seconds: histogram of request latency, by
+//     endpoint
+//   - climacell_retries_total: counter of retries, by endpoint
+//   - climacell_cache_hits_total: counter of cache hits, by endpoint
+//   - climacell_cache_misses_total: counter of cache misses, by endpoint
+//   - climacell_decode_errors_total: counter of responses that could not be
+//     deserialized, by endpoint
+type PrometheusMetrics struct {
+	mu           sync.Mutex
+	buckets      []float64
+	requests     map[requestKey]uint64
+	latencies    map[Endpoint]*histogram
+	retries      map[Endpoint]uint64
+	cacheHits    map[Endpoint]uint64
+	cacheMisses  map[Endpoint]uint64
+	decodeErrors map[Endpoint]uint64
+}
+
+type requestKey struct {
+	endpt Endpoint
+	code  string
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// NewPrometheusMetrics returns a PrometheusMetrics using the given latency
+// histogram buckets, in seconds. If no buckets are passed in,
+// DefaultLatencyBuckets is used.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &PrometheusMetrics{
+		buckets:      buckets,
+		requests:     make(map[requestKey]uint64),
+		latencies:    make(map[Endpoint]*histogram),
+		retries:      make(map[Endpoint]uint64),
+		cacheHits:    make(map[Endpoint]uint64),
+		cacheMisses:  make(map[Endpoint]uint64),
+		decodeErrors: make(map[Endpoint]uint64),
+	}
+}
+
+// ObserveRequest implements the Metrics interface.
+func (m *PrometheusMetrics) ObserveRequest(endpt Endpoint, statusCode int, latency time.Duration) {
+	code := "error"
+	if statusCode != 0 {
+		code = strconv.Itoa(statusCode)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestKey{endpt: endpt, code: code}]++
+
+	h, ok := m.latencies[endpt]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[endpt] = h
+	}
+	secs := latency.Seconds()
+	for i, upper := range m.buckets {
+		if secs <= upper {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += secs
+	h.count++
+}
+
+// IncRetries implements the Metrics interface.
+func (m *PrometheusMetrics) IncRetries(endpt Endpoint) { m.inc(m.retries, endpt) }
+
+// IncCacheHits implements the Metrics interface.
+func (m *PrometheusMetrics) IncCacheHits(endpt Endpoint) { m.inc(m.cacheHits, endpt) }
+
+// IncCacheMisses implements the Metrics interface.
+func (m *PrometheusMetrics) IncCacheMisses(endpt Endpoint) { m.inc(m.cacheMisses, endpt) }
+
+// IncDecodeErrors implements the Metrics interface.
+func (m *PrometheusMetrics) IncDecodeErrors(endpt Endpoint) { m.inc(m.decodeErrors, endpt) }
+
+func (m *PrometheusMetrics) inc(counter map[Endpoint]uint64, endpt Endpoint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counter[endpt]++
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pw := &promWriter{w: w}
+
+	pw.header("climacell_requests_total", "counter", "Requests sent to the ClimaCell API.")
+	reqKeys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		reqKeys = append(reqKeys, k)
+	}
+	sort.Slice(reqKeys, func(i, j int) bool {
+		if reqKeys[i].endpt != reqKeys[j].endpt {
+			return reqKeys[i].endpt < reqKeys[j].endpt
+		}
+		return reqKeys[i].code < reqKeys[j].code
+	})
+	for _, k := range reqKeys {
+		pw.printf("climacell_requests_total{code=%q,endpoint=%q} %d\n", k.code, k.endpt, m.requests[k])
+	}
+
+	pw.header("climacell_request_duration_seconds", "histogram",
+		"Latency of requests sent to the ClimaCell API.")
+	for _, endpt := range sortedEndpoints(m.latencies) {
+		h := m.latencies[endpt]
+		var cumulative uint64
+		for i, upper := range m.buckets {
+			cumulative += h.counts[i]
+			pw.printf("climacell_request_duration_seconds_bucket{endpoint=%q,le=%q} %d\n",
+				endpt, strconv.FormatFloat(upper, 'g', -1, 64), cumulative)
+		}
+		pw.printf("climacell_request_duration_seconds_bucket{endpoint=%q,le=\"+Inf\"} %d\n", endpt, h.count)
+		pw.printf("climacell_request_duration_seconds_sum{endpoint=%q} %s\n",
+			endpt, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		pw.printf("climacell_request_duration_seconds_count{endpoint=%q} %d\n", endpt, h.count)
+	}
+
+	pw.counter("climacell_retries_total", "Retried requests to the ClimaCell API.", m.retries)
+	pw.counter("climacell_cache_hits_total", "Responses served from the cache.", m.cacheHits)
+	pw.counter("climacell_cache_misses_total", "Responses not found in the cache.", m.cacheMisses)
+	pw.counter("climacell_decode_errors_total", "Responses that could not be deserialized.", m.decodeErrors)
+	return pw.n, pw.err
+}
+
+// promWriter writes Prometheus text format lines, keeping track of the bytes
+// written and the first error.
+type promWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (pw *promWriter) printf(format string, v ...interface{}) {
+	if pw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(pw.w, format, v...)
+	pw.n += int64(n)
+	pw.err = err
+}
+
+func (pw *promWriter) header(name, typ, help string) {
+	pw.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (pw *promWriter) counter(name, help string, values map[Endpoint]uint64) {
+	pw.header(name, "counter", help)
+	for _, endpt := range sortedEndpoints(values) {
+		pw.printf("%s{endpoint=%q} %d\n", name, endpt, values[endpt])
+	}
+}
+
+// sortedEndpoints returns the keys of a map keyed by Endpoint in sorted order.
+func sortedEndpoints(m interface{}) []Endpoint {
+	var endpts []Endpoint
+	switch m := m.(type) {
+	case map[Endpoint]uint64:
+		for endpt := range m {
+			endpts = append(endpts, endpt)
+		}
+	case map[Endpoint]*histogram:
+		for endpt := range m {
+			endpts = append(endpts, endpt)
+		}
+	}
+	sort.Slice(endpts, func(i, j int) bool { return endpts[i] < endpts[j] })
+	return endpts
+}
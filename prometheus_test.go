@@ -0,0 +1,66 @@
+package climacell
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMetricsFromClient(t *testing.T) {
+	server, _ := flakyServer(1, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	m := NewPrometheusMetrics(0.5, 1)
+	client := NewClient("test_api_key",
+		WithBaseURL(server.URL),
+		WithMetrics(m),
+		WithCache(NewLRUCache(10)),
+		WithRetryPolicy(&RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond}),
+	)
+
+	args := ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}}
+	for i := 0; i < 2; i++ {
+		_, err := client.HourlyForecast(args)
+		require.NoError(t, err)
+	}
+
+	metricsServer := httptest.NewServer(m)
+	defer metricsServer.Close()
+	res, err := http.Get(metricsServer.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	out := string(b)
+
+	assert.Contains(t, out, "# TYPE climacell_requests_total counter\n")
+	assert.Contains(t, out, `climacell_requests_total{code="200",endpoint="weather/forecast/hourly"} 1`)
+	assert.Contains(t, out, `climacell_requests_total{code="503",endpoint="weather/forecast/hourly"} 1`)
+	assert.Contains(t, out, `climacell_request_duration_seconds_bucket{endpoint="weather/forecast/hourly",le="0.5"} 2`)
+	assert.Contains(t, out, `climacell_request_duration_seconds_bucket{endpoint="weather/forecast/hourly",le="+Inf"} 2`)
+	assert.Contains(t, out, `climacell_request_duration_seconds_count{endpoint="weather/forecast/hourly"} 2`)
+	assert.Contains(t, out, `climacell_retries_total{endpoint="weather/forecast/hourly"} 1`)
+	assert.Contains(t, out, `climacell_cache_hits_total{endpoint="weather/forecast/hourly"} 1`)
+	assert.Contains(t, out, `climacell_cache_misses_total{endpoint="weather/forecast/hourly"} 1`)
+}
+
+func TestPrometheusMetricsDecodeErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`not json`))
+	}))
+	defer server.Close()
+
+	m := NewPrometheusMetrics()
+	client := NewClient("test_api_key", WithBaseURL(server.URL), WithMetrics(m))
+	_, err := client.Nowcast(ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}})
+	require.Error(t, err)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), `climacell_decode_errors_total{endpoint="weather/nowcast"} 1`)
+}
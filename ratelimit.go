@@ -0,0 +1,282 @@
+package climacell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RateLimit is a quota for how many requests can be sent within a window of
+// time, such as 100 requests per hour.
+type RateLimit struct {
+	// Requests is the number of requests allowed per window.
+	Requests int
+	// Per is the length of the window.
+	Per time.Duration
+}
+
+// RateLimitBudget indicates how much of a RateLimit's quota is left.
+type RateLimitBudget struct {
+	RateLimit
+	// Remaining is the number of requests that can be sent right now
+	// without waiting.
+	Remaining int
+	// FullIn is how long until the full quota for this RateLimit is
+	// available again, assuming no more requests are sent.
+	FullIn time.Duration
+}
+
+// RateLimiter is a client-side token bucket rate limiter for keeping requests
+// to the ClimaCell API within a plan's quotas. It can enforce several windows
+// at once, such as calls per second, per hour, and per day; a request is only
+// allowed if there is room for it in every window.
+//
+// A RateLimiter is safe for concurrent use, and can be shared between Clients
+// that use the same API key.
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets []*tokenBucket
+	// returns the current time; swapped out in test coverage
+	now func() time.Time
+}
+
+// NewRateLimiter returns a RateLimiter enforcing the given RateLimits. Each
+// window starts out with its full quota available. RateLimits with a
+// non-positive Requests or Per are ignored.
+func NewRateLimiter(limits ...RateLimit) *RateLimiter {
+	l := &RateLimiter{now: time.Now}
+	start := l.now()
+	for _, lim := range limits {
+		if lim.Requests <= 0 || lim.Per <= 0 {
+			continue
+		}
+		l.buckets = append(l.buckets, &tokenBucket{
+			limit:  lim,
+			tokens: float64(lim.Requests),
+			last:   start,
+		})
+	}
+	return l
+}
+
+// Wait blocks until a request can be sent without going over any of the
+// RateLimiter's quotas, and then takes up a slot for that request. If the
+// context is done before a slot is free, the context's error is returned and
+// no slot is taken.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		wait := l.reserve()
+		if wait == 0 {
+			return nil
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return errors.WithMessage(ctx.Err(), "waiting for rate limiter")
+		}
+	}
+}
+
+// Allow takes up a slot for a request and returns true if a request can be
+// sent right now without going over any quotas, or returns false without
+// taking up a slot otherwise.
+func (l *RateLimiter) Allow() bool { return l.reserve() == 0 }
+
+// Remaining returns the remaining budget for each of the RateLimiter's
+// quotas, in the order the RateLimits were passed to NewRateLimiter.
+func (l *RateLimiter) Remaining() []RateLimitBudget {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	budgets := make([]RateLimitBudget, len(l.buckets))
+	for i, b := range l.buckets {
+		b.refill(now)
+		budgets[i] = RateLimitBudget{
+			RateLimit: b.limit,
+			Remaining: int(math.Floor(b.tokens)),
+			FullIn:    b.timeUntil(float64(b.limit.Requests)),
+		}
+	}
+	return budgets
+}
+
+// reserve takes a token from every bucket and returns 0 if every bucket has
+// one available. Otherwise, no tokens are taken, and reserve returns how long
+// to wait before trying again.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var wait time.Duration
+	for _, b := range l.buckets {
+		b.refill(now)
+		if d := b.timeUntil(1); d > wait {
+			wait = d
+		}
+	}
+	if wait > 0 {
+		return wait
+	}
+
+	for _, b := range l.buckets {
+		b.tokens--
+	}
+	return 0
+}
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens that accumulated in the bucket since it was last
+// refilled.
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return
+	}
+	b.last = now
+
+	b.tokens += float64(b.limit.Requests) * float64(elapsed) / float64(b.limit.Per)
+	if max := float64(b.limit.Requests); b.tokens > max {
+		b.tokens = max
+	}
+}
+
+// timeUntil returns how long until the bucket has n tokens.
+func (b *tokenBucket) timeUntil(n float64) time.Duration {
+	if b.tokens >= n {
+		return 0
+	}
+	missing := n - b.tokens
+	return time.Duration(math.Ceil(missing * float64(b.limit.Per) / float64(b.limit.Requests)))
+}
+
+// RateLimitError is returned when the ClimaCell API responds with a 429
+// because a quota on the plan for the API key was used up. Its fields are
+// parsed from the response's rate limit headers so that callers can tell
+// how long to back off for.
+type RateLimitError struct {
+	// Limit is the number of requests allowed in the current window, from
+	// the RateLimit-Limit header, or -1 if the header was absent.
+	Limit int
+	// Remaining is the number of requests remaining in the current window,
+	// from the RateLimit-Remaining header, or -1 if the header was absent.
+	Remaining int
+	// Reset is when the current window resets, from the RateLimit-Reset
+	// header. Zero if the header was absent.
+	Reset time.Time
+	// RetryAfter is how long the API asked us to wait before retrying, from
+	// the Retry-After header. Zero if the header was absent.
+	RetryAfter time.Duration
+	// Windows contains the limit and remaining requests for each window
+	// the API reported headers like X-RateLimit-Remaining-Hour for, keyed
+	// by the lowercase name of the window, such as "second", "hour", or
+	// "day".
+	Windows map[string]RateLimitWindow
+	// Response is the JSON body of the response if it had one.
+	Response *ErrorResponse
+}
+
+// RateLimitWindow is the limit and remaining requests for a single window
+// reported on a 429 response.
+type RateLimitWindow struct {
+	// Limit is the number of requests allowed in the window, or -1 if it
+	// was not reported.
+	Limit int
+	// Remaining is the number of requests remaining in the window, or -1
+	// if it was not reported.
+	Remaining int
+}
+
+func (err *RateLimitError) Error() string {
+	msg := "429 API error: rate limit exceeded"
+	if err.Response != nil && err.Response.Message != "" {
+		msg = fmt.Sprintf("429 API error: %s", err.Response.Message)
+	}
+	if !err.Reset.IsZero() {
+		msg += fmt.Sprintf(" (resets at %s)", err.Reset.Format(time.RFC3339))
+	}
+	return msg
+}
+
+// newRateLimitError parses a RateLimitError from a 429 response.
+func newRateLimitError(res *http.Response) *RateLimitError {
+	h := res.Header
+	err := &RateLimitError{
+		Limit:     headerInt(h, "RateLimit-Limit", "X-RateLimit-Limit"),
+		Remaining: headerInt(h, "RateLimit-Remaining", "X-RateLimit-Remaining"),
+	}
+	if retryAfter, ok := parseRetryAfter(h); ok {
+		err.RetryAfter = retryAfter
+	}
+
+	if reset := headerInt(h, "RateLimit-Reset", "X-RateLimit-Reset"); reset >= 0 {
+		// The reset is usually the number of seconds until the window
+		// resets, but some gateways send a Unix timestamp instead.
+		if reset > 1e9 {
+			err.Reset = time.Unix(int64(reset), 0)
+		} else {
+			err.Reset = time.Now().Add(time.Duration(reset) * time.Second)
+		}
+	} else if err.RetryAfter > 0 {
+		err.Reset = time.Now().Add(err.RetryAfter)
+	}
+
+	for k := range h {
+		const limitPrefix, remainingPrefix = "X-Ratelimit-Limit-", "X-Ratelimit-Remaining-"
+		var window string
+		switch {
+		case strings.HasPrefix(k, limitPrefix):
+			window = k[len(limitPrefix):]
+		case strings.HasPrefix(k, remainingPrefix):
+			window = k[len(remainingPrefix):]
+		default:
+			continue
+		}
+
+		if err.Windows == nil {
+			err.Windows = make(map[string]RateLimitWindow)
+		}
+		err.Windows[strings.ToLower(window)] = RateLimitWindow{
+			Limit:     headerInt(h, limitPrefix+window),
+			Remaining: headerInt(h, remainingPrefix+window),
+		}
+	}
+
+	if b, readErr := ioutil.ReadAll(res.Body); readErr == nil && len(b) > 0 {
+		var errRes ErrorResponse
+		if json.Unmarshal(b, &errRes) == nil {
+			errRes.StatusCode = res.StatusCode
+			err.Response = &errRes
+		}
+	}
+	return err
+}
+
+// headerInt returns the value of the first of the given headers that is
+// present as a non-negative integer, or -1 if none are.
+func headerInt(h http.Header, keys ...string) int {
+	for _, k := range keys {
+		if n, err := strconv.Atoi(strings.TrimSpace(h.Get(k))); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return -1
+}
@@ -0,0 +1,118 @@
+package climacell
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock for test coverage that only moves when advanced.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestRateLimiter(clock *fakeClock, limits ...RateLimit) *RateLimiter {
+	l := NewRateLimiter(limits...)
+	l.now = clock.now
+	for _, b := range l.buckets {
+		b.last = clock.now()
+	}
+	return l
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)}
+	l := newTestRateLimiter(clock,
+		RateLimit{Requests: 2, Per: time.Second},
+		RateLimit{Requests: 3, Per: time.Hour},
+	)
+
+	assert.True(t, l.Allow())
+	assert.True(t, l.Allow())
+	assert.False(t, l.Allow(), "per-second quota should be used up")
+
+	clock.advance(time.Second)
+	assert.True(t, l.Allow())
+	assert.False(t, l.Allow(), "per-hour quota should be used up")
+
+	budgets := l.Remaining()
+	require.Len(t, budgets, 2)
+	assert.Equal(t, 1, budgets[0].Remaining)
+	assert.Equal(t, 0, budgets[1].Remaining)
+	assert.Equal(t, time.Hour-time.Second, budgets[1].FullIn)
+
+	clock.advance(20 * time.Minute)
+	assert.True(t, l.Allow())
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := NewRateLimiter(RateLimit{Requests: 1, Per: 50 * time.Millisecond})
+
+	require.NoError(t, l.Wait(context.Background()))
+	start := time.Now()
+	require.NoError(t, l.Wait(context.Background()))
+	assert.True(t, time.Since(start) >= 40*time.Millisecond, "should have waited for a slot")
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	l := NewRateLimiter(RateLimit{Requests: 1, Per: time.Hour})
+	require.NoError(t, l.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := l.Wait(ctx)
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+}
+
+func TestClientWaitsOnRateLimiter(t *testing.T) {
+	server := serverMock()
+	defer server.Close()
+
+	client := New("test_api_key")
+	client.baseURL = server.URL
+	client.SetRateLimiter(NewRateLimiter(RateLimit{Requests: 1, Per: time.Hour}))
+
+	args := ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}}
+	_, err := client.RealTime(args)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.RealTimeWithContext(ctx, args)
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+}
+
+func TestRateLimitErrorFromResponse(t *testing.T) {
+	server, _ := flakyServer(1, http.StatusTooManyRequests, http.Header{
+		"Ratelimit-Limit":            []string{"100"},
+		"Ratelimit-Remaining":        []string{"0"},
+		"Ratelimit-Reset":            []string{"30"},
+		"X-Ratelimit-Limit-Hour":     []string{"100"},
+		"X-Ratelimit-Remaining-Hour": []string{"0"},
+		"X-Ratelimit-Limit-Day":      []string{"1000"},
+		"X-Ratelimit-Remaining-Day":  []string{"250"},
+	})
+	defer server.Close()
+
+	client := New("test_api_key")
+	client.baseURL = server.URL
+
+	_, err := client.HourlyForecast(ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}})
+	var rateErr *RateLimitError
+	require.True(t, stderrors.As(err, &rateErr), "expected a RateLimitError, got %v", err)
+	assert.Equal(t, 100, rateErr.Limit)
+	assert.Equal(t, 0, rateErr.Remaining)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), rateErr.Reset, 5*time.Second)
+	assert.Equal(t, map[string]RateLimitWindow{
+		"hour": {Limit: 100, Remaining: 0},
+		"day":  {Limit: 1000, Remaining: 250},
+	}, rateErr.Windows)
+	assert.Nil(t, rateErr.Response)
+}
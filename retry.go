@@ -0,0 +1,191 @@
+package climacell
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy indicates how a Client retries requests that fail with errors
+// that might succeed on another try, such as a 503 from the API or a network
+// error.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent,
+	// including the first attempt. If MaxAttempts is 1 or less, requests
+	// are not retried.
+	MaxAttempts int
+	// BaseBackoff is how long to wait before the first retry. Each retry
+	// after that waits twice as long as the one before it.
+	BaseBackoff time.Duration
+	// MaxBackoff, if nonzero, caps how long to wait between attempts. Note
+	// that a Retry-After header on a response is honored even if it is
+	// longer than MaxBackoff.
+	MaxBackoff time.Duration
+	// Jitter is the fraction, between 0 and 1, of each backoff that is
+	// randomized, so that many clients failing at once don't all retry at
+	// the same time. For example, with a Jitter of 0.2, a 1 second backoff
+	// lasts for between 0.8 and 1 seconds.
+	Jitter float64
+	// Retryable indicates whether a failed request should be retried based
+	// on its error. If nil, DefaultRetryable is used.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy that sends up to 3 attempts for a
+// request, starting with a half-second backoff.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.2,
+		Retryable:   DefaultRetryable,
+	}
+}
+
+// DefaultRetryable returns true for errors that are likely to be temporary:
+// network errors, 500 ErrorResponses, RateLimitErrors, and 500, 502, 503, and
+// 504 responses. Errors from a cancelled or timed out context are never
+// retried.
+func DefaultRetryable(err error) bool {
+	// context errors are checked first, since context.DeadlineExceeded is
+	// also a net.Error
+	cause := errors.Cause(err)
+	if cause == context.Canceled || cause == context.DeadlineExceeded {
+		return false
+	}
+
+	switch err := cause.(type) {
+	case *ErrorResponse:
+		return err.StatusCode >= 500
+	case *RateLimitError:
+		return true
+	case *UnexpectedStatusError:
+		switch err.StatusCode {
+		case http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	case net.Error:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable == nil {
+		return DefaultRetryable(err)
+	}
+	return p.Retryable(err)
+}
+
+// backoff returns how long to wait before the given retry, where retry 1 is
+// the first retry after the initial attempt.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := p.BaseBackoff
+	for i := 1; i < retry; i++ {
+		if d*2 < d || (p.MaxBackoff > 0 && d >= p.MaxBackoff) {
+			break
+		}
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		d -= time.Duration(rand.Float64() * jitter * float64(d))
+	}
+	return d
+}
+
+// withRetries calls send, retrying it per the Client's RetryPolicy. send
+// returns the headers of the response it got, if any, so that a Retry-After
+// header can be honored. If the request is not idempotent, it is only retried
+// on a RateLimitError, since the API did not process the request.
+func (c *Client) withRetries(
+	ctx context.Context,
+	endpt Endpoint,
+	idempotent bool,
+	send func() (http.Header, error),
+) error {
+	p := c.retry
+	for attempt := 1; ; attempt++ {
+		header, err := send()
+		if err == nil || p == nil || attempt >= p.MaxAttempts ||
+			ctx.Err() != nil || !p.retryable(err) {
+			return err
+		}
+		if _, rateLimited := errors.Cause(err).(*RateLimitError); !idempotent && !rateLimited {
+			return err
+		}
+
+		wait := p.backoff(attempt)
+		if retryAfter, ok := parseRetryAfter(header); ok {
+			wait = retryAfter
+		} else if rateErr, ok := errors.Cause(err).(*RateLimitError); ok && !rateErr.Reset.IsZero() {
+			if untilReset := time.Until(rateErr.Reset); untilReset > wait {
+				wait = untilReset
+			}
+		}
+
+		c.logf("retrying request to %s in %v after error: %v", endpt, wait, err)
+		if c.metrics != nil {
+			c.metrics.IncRetries(endpt)
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return errors.WithMessagef(ctx.Err(), "waiting to retry request to %s", endpt)
+		}
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds, or an HTTP date.
+func parseRetryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	tm, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := time.Until(tm); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// idempotent returns whether requests with the given HTTP method can safely
+// be sent more than once.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,146 @@
+package climacell
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyServer returns a test server that responds to the first failures
+// requests with the given status code, and with an empty JSON array after
+// that.
+func flakyServer(failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	return server, &calls
+}
+
+func TestRetriesUntilSuccess(t *testing.T) {
+	server, calls := flakyServer(2, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	client := New("test_api_key")
+	client.baseURL = server.URL
+	client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond})
+
+	_, err := client.HourlyForecast(ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(calls))
+}
+
+func TestRetriesGiveUpAfterMaxAttempts(t *testing.T) {
+	server, calls := flakyServer(5, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	client := New("test_api_key")
+	client.baseURL = server.URL
+	client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond})
+
+	_, err := client.HourlyForecast(ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}})
+	if statusErr, ok := errors.Cause(err).(*UnexpectedStatusError); assert.True(t, ok) {
+		assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+	}
+	assert.EqualValues(t, 2, atomic.LoadInt32(calls))
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	server, calls := flakyServer(1, http.StatusNotImplemented, nil)
+	defer server.Close()
+
+	client := New("test_api_key")
+	client.baseURL = server.URL
+	client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond})
+
+	_, err := client.HourlyForecast(ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}})
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+}
+
+func TestRetriesServerErrorsWithoutStatusCode(t *testing.T) {
+	for _, body := range []string{
+		`{"message": "oops"}`,
+		`<html><body>Internal Server Error</body></html>`,
+	} {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(body))
+		}))
+
+		client := New("test_api_key")
+		client.baseURL = server.URL
+		client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond})
+
+		_, err := client.HourlyForecast(ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}})
+		server.Close()
+		switch err := errors.Cause(err).(type) {
+		case *ErrorResponse:
+			assert.Equal(t, http.StatusInternalServerError, err.StatusCode, body)
+			assert.Equal(t, "500 API error: oops", err.Error())
+		case *UnexpectedStatusError:
+			assert.Equal(t, http.StatusInternalServerError, err.StatusCode, body)
+		default:
+			t.Errorf("unexpected error for %s: %v", body, err)
+		}
+		assert.EqualValues(t, 3, atomic.LoadInt32(&calls), body)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	server, calls := flakyServer(1, http.StatusServiceUnavailable, http.Header{
+		"Retry-After": []string{"1"},
+	})
+	defer server.Close()
+
+	client := New("test_api_key")
+	client.baseURL = server.URL
+	client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond})
+
+	start := time.Now()
+	_, err := client.HourlyForecast(ForecastArgs{Location: LatLon{Lat: 11.3, Lon: 52.4}})
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) >= time.Second, "should have waited for Retry-After")
+	assert.EqualValues(t, 2, atomic.LoadInt32(calls))
+}
+
+func TestDefaultRetryable(t *testing.T) {
+	assert.True(t, DefaultRetryable(&ErrorResponse{StatusCode: 500}))
+	assert.True(t, DefaultRetryable(&UnexpectedStatusError{StatusCode: 503}))
+	assert.False(t, DefaultRetryable(&ErrorResponse{StatusCode: 400}))
+	assert.False(t, DefaultRetryable(context.Canceled))
+	assert.False(t, DefaultRetryable(context.DeadlineExceeded))
+	assert.False(t, DefaultRetryable(errors.WithMessage(context.DeadlineExceeded, "sending request")))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := &RetryPolicy{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, p.backoff(1))
+	assert.Equal(t, 2*time.Second, p.backoff(2))
+	assert.Equal(t, 4*time.Second, p.backoff(3))
+	assert.Equal(t, 5*time.Second, p.backoff(4))
+	assert.Equal(t, 5*time.Second, p.backoff(40))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.backoff(1)
+		assert.True(t, d > 500*time.Millisecond && d <= time.Second, "backoff %v out of range", d)
+	}
+}
@@ -0,0 +1,277 @@
+package climacell
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RollupOptions configure how weather samples are grouped into windows by
+// RollupHourly and RollupHistoricalClimaCell.
+type RollupOptions struct {
+	// Window is the length of each window. It must evenly divide a day,
+	// such as 6 hours. The default is a day.
+	Window time.Duration
+	// Offset is how long after midnight the first window of each day
+	// starts, and must be less than a day. For example, with an Offset of
+	// 6 hours and a Window of a day, like a ForecastDay, each window goes
+	// from 6AM to 6AM.
+	Offset time.Duration
+	// Location is the time zone windows are aligned to. The default is UTC.
+	Location *time.Location
+}
+
+// Rollup is a summary of the weather samples in a window of time.
+type Rollup struct {
+	// Start and End are the time range of the window. Samples with an
+	// ObservationTime from Start up to, but not including, End are in it.
+	Start time.Time
+	End   time.Time
+	// Samples is the number of samples in the window.
+	Samples int
+	// Fields contains the summary of each field the samples had values for.
+	Fields map[Field]*FieldRollup
+	// WeatherCode is the most common weather code in the window, with ties
+	// going to the more severe weather code. It is blank if no samples had
+	// a weather code.
+	WeatherCode WeatherCode
+}
+
+// Field returns the summary of a field, or nil if no samples in the window had
+// a value for it.
+func (r *Rollup) Field(f Field) *FieldRollup { return r.Fields[f] }
+
+// FieldRollup is the summary of a field's values in a window of time.
+type FieldRollup struct {
+	// Min and Max are the minimum and maximum values, and when they were
+	// observed. If a value is observed more than once, the earliest is used.
+	Min *FloatAtTimeValue
+	Max *FloatAtTimeValue
+	// Mean is the average value.
+	Mean *FloatValue
+	// Sum, for precipitation, is the total precipitation. Each sample's
+	// rate is weighted by the time until the next sample, or for the last
+	// sample, the time since the one before it, so samples can have any
+	// timestep. Its Units are the rate's Units without "/hr", such as "mm".
+	Sum *FloatValue
+	// Count is the number of samples with a value for the field.
+	Count int
+
+	total float64
+	// sum is the total of each value multiplied by its sample's timestep,
+	// in hours.
+	sum float64
+}
+
+// MinAndMax returns the minimum and maximum in the same format as the fields
+// of a ForecastDay.
+func (r *FieldRollup) MinAndMax() ForecastMinAndMax {
+	return ForecastMinAndMax{
+		{ObservationTime: r.Min.ObservationTime, Min: r.Min.Value},
+		{ObservationTime: r.Max.ObservationTime, Max: r.Max.Value},
+	}
+}
+
+// rollupFields are the fields that are summarized in a Rollup, and how to get
+// them from a WeatherType. Wind direction is left out, since the mean of
+// angles is not meaningful.
+var rollupFields = []struct {
+	field Field
+	get   func(w *WeatherType) *FloatValue
+}{
+	{FieldTemp, func(w *WeatherType) *FloatValue { return w.Temp }},
+	{FieldFeelsLike, func(w *WeatherType) *FloatValue { return w.FeelsLike }},
+	{FieldDewPoint, func(w *WeatherType) *FloatValue { return w.DewPoint }},
+	{FieldHumidity, func(w *WeatherType) *FloatValue { return w.Humidity }},
+	{FieldWindSpeed, func(w *WeatherType) *FloatValue { return w.WindSpeed }},
+	{FieldWindGust, func(w *WeatherType) *FloatValue { return w.WindGust }},
+	{FieldBaroPressure, func(w *WeatherType) *FloatValue { return w.BaroPressure }},
+	{FieldPrecipitation, func(w *WeatherType) *FloatValue { return w.Precipitation }},
+	{FieldPrecipitationProbability, func(w *WeatherType) *FloatValue { return w.PrecipitationProbability }},
+	{FieldVisibility, func(w *WeatherType) *FloatValue { return w.Visibility }},
+	{FieldCloudCover, func(w *WeatherType) *FloatValue { return w.CloudCover }},
+	{FieldCloudBase, func(w *WeatherType) *FloatValue { return w.CloudBase }},
+	{FieldCloudCeiling, func(w *WeatherType) *FloatValue { return w.CloudCeiling }},
+	{FieldSurfaceShortwaveRadiation, func(w *WeatherType) *FloatValue { return w.SurfaceShortwaveRadiation }},
+}
+
+// rollupSample is the parts of a weather sample that are rolled up.
+type rollupSample struct {
+	observed time.Time
+	weather  *WeatherType
+	// timestep is how long until the next sample.
+	timestep time.Duration
+}
+
+// RollupHourly groups hourly forecast samples into windows, such as calendar
+// days, and summarizes each window, returning the Rollups in order. Samples
+// without an ObservationTime are skipped.
+func RollupHourly(samples []HourlyForecast, opts RollupOptions) ([]Rollup, error) {
+	rs := make([]rollupSample, len(samples))
+	for i := range samples {
+		rs[i] = rollupSample{observed: samples[i].ObservationTime.Value, weather: &samples[i].WeatherType}
+	}
+	return rollup(rs, opts)
+}
+
+// RollupHistoricalClimaCell is like RollupHourly, but for historical samples,
+// which can have any timestep.
+func RollupHistoricalClimaCell(samples []HistoricalClimaCell, opts RollupOptions) ([]Rollup, error) {
+	rs := make([]rollupSample, len(samples))
+	for i := range samples {
+		rs[i] = rollupSample{observed: samples[i].ObservationTime.Value, weather: &samples[i].WeatherType}
+	}
+	return rollup(rs, opts)
+}
+
+func rollup(samples []rollupSample, opts RollupOptions) ([]Rollup, error) {
+	const day = 24 * time.Hour
+	if opts.Window == 0 {
+		opts.Window = day
+	}
+	if opts.Window < 0 || day%opts.Window != 0 {
+		return nil, errors.Errorf("window %s does not evenly divide a day", opts.Window)
+	}
+	if opts.Offset < 0 || opts.Offset >= day {
+		return nil, errors.Errorf("offset %s is not from 0 up to 24 hours", opts.Offset)
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	samples = timesteps(samples)
+	windows := make(map[time.Time]*Rollup)
+	codeCounts := make(map[time.Time]map[WeatherCode]int)
+	for _, s := range samples {
+		start, end := opts.window(s.observed)
+		r, ok := windows[start]
+		if !ok {
+			r = &Rollup{Start: start, End: end, Fields: make(map[Field]*FieldRollup)}
+			windows[start] = r
+			codeCounts[start] = make(map[WeatherCode]int)
+		}
+		r.Samples++
+
+		for _, rf := range rollupFields {
+			if v := rf.get(s.weather); v != nil && v.Value != nil {
+				r.add(rf.field, s.observed, v, s.timestep.Hours())
+			}
+		}
+		if code, ok := s.weather.WeatherCode.GetValue(); ok {
+			codeCounts[start][code]++
+		}
+	}
+
+	rollups := make([]Rollup, 0, len(windows))
+	for start, r := range windows {
+		r.WeatherCode = dominantWeatherCode(codeCounts[start])
+		for f, fr := range r.Fields {
+			mean := fr.total / float64(fr.Count)
+			fr.Mean = &FloatValue{Value: &mean, Units: fr.Min.Value.Units}
+			if f == FieldPrecipitation {
+				sum := fr.sum
+				fr.Sum = &FloatValue{Value: &sum, Units: strings.TrimSuffix(fr.Min.Value.Units, "/hr")}
+			}
+		}
+		rollups = append(rollups, *r)
+	}
+	sort.Slice(rollups, func(i, j int) bool { return rollups[i].Start.Before(rollups[j].Start) })
+	return rollups, nil
+}
+
+// timesteps returns the samples with an observation time, sorted by it, with
+// their timesteps set. The last sample's timestep is the one before it, and if
+// there is only one sample, its timestep is an hour.
+func timesteps(samples []rollupSample) []rollupSample {
+	observed := samples[:0]
+	for _, s := range samples {
+		if !s.observed.IsZero() {
+			observed = append(observed, s)
+		}
+	}
+	sort.SliceStable(observed, func(i, j int) bool { return observed[i].observed.Before(observed[j].observed) })
+
+	for i := range observed {
+		switch {
+		case i+1 < len(observed):
+			observed[i].timestep = observed[i+1].observed.Sub(observed[i].observed)
+		case i > 0:
+			observed[i].timestep = observed[i-1].timestep
+		default:
+			observed[i].timestep = time.Hour
+		}
+	}
+	return observed
+}
+
+// window returns the start and end of the window a time is in.
+func (opts RollupOptions) window(t time.Time) (start, end time.Time) {
+	t = t.In(opts.Location)
+	y, m, d := t.Date()
+	dayStart := time.Date(y, m, d, 0, 0, 0, 0, opts.Location).Add(opts.Offset)
+	for dayStart.After(t) {
+		y, m, d = dayStart.AddDate(0, 0, -1).Date()
+		dayStart = time.Date(y, m, d, 0, 0, 0, 0, opts.Location).Add(opts.Offset)
+	}
+
+	// windows within a day are counted from the day's start, and the next
+	// day's start is found by calendar date, so days with daylight saving
+	// time changes have the right length. On those days, the last window is
+	// cut short at the next day's start, rather than overlapping the next
+	// day's first window.
+	y, m, d = dayStart.Date()
+	nextDay := time.Date(y, m, d+1, 0, 0, 0, 0, opts.Location).Add(opts.Offset)
+	if opts.Window == 24*time.Hour {
+		return dayStart, nextDay
+	}
+	n := t.Sub(dayStart) / opts.Window
+	start = dayStart.Add(n * opts.Window)
+	end = start.Add(opts.Window)
+	if end.After(nextDay) {
+		end = nextDay
+	}
+	return start, end
+}
+
+func (r *Rollup) add(f Field, observed time.Time, v *FloatValue, hours float64) {
+	fr, ok := r.Fields[f]
+	if !ok {
+		at := &FloatAtTimeValue{ObservationTime: observed, Value: v}
+		r.Fields[f] = &FieldRollup{Min: at, Max: at, Count: 1, total: *v.Value, sum: *v.Value * hours}
+		return
+	}
+
+	fr.Count++
+	fr.total += *v.Value
+	fr.sum += *v.Value * hours
+	if *v.Value < *fr.Min.Value.Value ||
+		(*v.Value == *fr.Min.Value.Value && observed.Before(fr.Min.ObservationTime)) {
+		fr.Min = &FloatAtTimeValue{ObservationTime: observed, Value: v}
+	}
+	if *v.Value > *fr.Max.Value.Value ||
+		(*v.Value == *fr.Max.Value.Value && observed.Before(fr.Max.ObservationTime)) {
+		fr.Max = &FloatAtTimeValue{ObservationTime: observed, Value: v}
+	}
+}
+
+// dominantWeatherCode returns the most common weather code, breaking ties by
+// severity, then alphabetically for unknown weather codes.
+func dominantWeatherCode(counts map[WeatherCode]int) WeatherCode {
+	var dominant WeatherCode
+	var max int
+	for code, n := range counts {
+		switch {
+		case n > max:
+		case n < max:
+			continue
+		case code.Severity() > dominant.Severity():
+		case code.Severity() == dominant.Severity() && code < dominant:
+		default:
+			continue
+		}
+		dominant, max = code, n
+	}
+	return dominant
+}
@@ -0,0 +1,196 @@
+package climacell
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hourlySamples returns a sample for each hour starting at start, with the
+// temperatures, precipitation rates, and weather codes passed in.
+func hourlySamples(start time.Time, temps, precip []float64, codes []WeatherCode) []HourlyForecast {
+	samples := make([]HourlyForecast, len(temps))
+	for i := range temps {
+		temp, rate, code := temps[i], precip[i], codes[i]
+		samples[i].ObservationTime = DateValue{Value: start.Add(time.Duration(i) * time.Hour)}
+		samples[i].Temp = &FloatValue{Value: &temp, Units: "C"}
+		samples[i].Precipitation = &FloatValue{Value: &rate, Units: "mm/hr"}
+		samples[i].WeatherCode = &WeatherCodeValue{Value: &code}
+	}
+	return samples
+}
+
+func TestRollupHourlyCalendarDays(t *testing.T) {
+	// UTC-4, like New York in the summer
+	est := time.FixedZone("EDT", -4*60*60)
+	start := time.Date(2020, 9, 1, 22, 0, 0, 0, est)
+	samples := hourlySamples(start,
+		[]float64{20, 18, 17, 15, 15, 16},
+		[]float64{0, 1.5, 0, 0.5, 0.25, 0},
+		[]WeatherCode{WeatherCodeClear, WeatherCodeRain, WeatherCodeCloudy, WeatherCodeCloudy, WeatherCodeRain, WeatherCodeRain},
+	)
+
+	rollups, err := RollupHourly(samples, RollupOptions{Location: est})
+	require.NoError(t, err)
+	require.Len(t, rollups, 2)
+
+	first := rollups[0]
+	assert.True(t, time.Date(2020, 9, 1, 0, 0, 0, 0, est).Equal(first.Start))
+	assert.True(t, time.Date(2020, 9, 2, 0, 0, 0, 0, est).Equal(first.End))
+	assert.Equal(t, 2, first.Samples)
+	// a tie goes to the more severe weather code
+	assert.Equal(t, WeatherCodeRain, first.WeatherCode)
+
+	second := rollups[1]
+	assert.Equal(t, 4, second.Samples)
+	assert.Equal(t, WeatherCodeRain, second.WeatherCode)
+
+	temp := second.Field(FieldTemp)
+	require.NotNil(t, temp)
+	assert.Equal(t, 4, temp.Count)
+	min, _ := temp.Min.GetValue()
+	assert.Equal(t, 15.0, min)
+	// the earliest of equal minimums is used
+	assert.True(t, start.Add(3*time.Hour).Equal(temp.Min.ObservationTime))
+	max, _ := temp.Max.GetValue()
+	assert.Equal(t, 17.0, max)
+	mean, _ := temp.Mean.GetValue()
+	assert.Equal(t, 15.75, mean)
+	assert.Equal(t, "C", temp.Mean.Units)
+	assert.Nil(t, temp.Sum)
+
+	minAndMax := temp.MinAndMax()
+	assert.Equal(t, temp.Min, minAndMax.Min())
+	assert.Equal(t, temp.Max, minAndMax.Max())
+
+	precip := second.Field(FieldPrecipitation)
+	require.NotNil(t, precip)
+	sum, _ := precip.Sum.GetValue()
+	assert.Equal(t, 0.75, sum)
+	assert.Equal(t, "mm", precip.Sum.Units)
+
+	assert.Nil(t, second.Field(FieldHumidity))
+}
+
+func TestRollupCustomWindows(t *testing.T) {
+	start := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	temps := make([]float64, 24)
+	precip := make([]float64, 24)
+	codes := make([]WeatherCode, 24)
+	for i := range temps {
+		temps[i] = float64(i)
+		codes[i] = WeatherCodeClear
+	}
+	samples := hourlySamples(start, temps, precip, codes)
+
+	// 6AM to 6AM, like a ForecastDay
+	rollups, err := RollupHourly(samples, RollupOptions{Offset: 6 * time.Hour})
+	require.NoError(t, err)
+	require.Len(t, rollups, 2)
+	assert.Equal(t, start.Add(-18*time.Hour), rollups[0].Start)
+	assert.Equal(t, 6, rollups[0].Samples)
+	assert.Equal(t, start.Add(6*time.Hour), rollups[1].Start)
+	assert.Equal(t, 18, rollups[1].Samples)
+
+	rollups, err = RollupHourly(samples, RollupOptions{Window: 6 * time.Hour})
+	require.NoError(t, err)
+	require.Len(t, rollups, 4)
+	for i, r := range rollups {
+		assert.Equal(t, start.Add(time.Duration(i)*6*time.Hour), r.Start)
+		assert.Equal(t, 6, r.Samples)
+	}
+
+	_, err = RollupHourly(samples, RollupOptions{Window: 7 * time.Hour})
+	assert.Error(t, err)
+
+	// offsets of a day or more, or negative offsets, are rejected rather
+	// than never finding a window's start, or ending windows before they
+	// start
+	_, err = RollupHourly(samples, RollupOptions{Offset: 30 * time.Hour})
+	assert.Error(t, err)
+	_, err = RollupHourly(samples, RollupOptions{Offset: 24 * time.Hour})
+	assert.Error(t, err)
+	_, err = RollupHourly(samples, RollupOptions{Offset: -25 * time.Hour})
+	assert.Error(t, err)
+}
+
+func TestRollupDaylightSavingTime(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("loading time zone: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		day     time.Time
+		windows []int
+	}{
+		// on a 25-hour day, the extra hour gets its own window
+		{"fall back", time.Date(2020, 11, 1, 0, 0, 0, 0, ny), []int{6, 6, 6, 6, 1, 5}},
+		// on a 23-hour day, the last window is an hour short
+		{"spring forward", time.Date(2020, 3, 8, 0, 0, 0, 0, ny), []int{6, 6, 6, 5, 6, 1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			temps := make([]float64, 30)
+			codes := make([]WeatherCode, 30)
+			samples := hourlySamples(tc.day, temps, temps, codes)
+
+			rollups, err := RollupHourly(samples, RollupOptions{Window: 6 * time.Hour, Location: ny})
+			require.NoError(t, err)
+
+			samplesPerWindow := make([]int, len(rollups))
+			for i, r := range rollups {
+				samplesPerWindow[i] = r.Samples
+				if i > 0 {
+					assert.False(t, r.Start.Before(rollups[i-1].End),
+						"window starting at %s overlaps the one before it", r.Start)
+				}
+			}
+			assert.Equal(t, tc.windows, samplesPerWindow)
+
+			nextDay := time.Date(tc.day.Year(), tc.day.Month(), tc.day.Day()+1, 0, 0, 0, 0, ny)
+			for _, r := range rollups {
+				if r.Start.Before(nextDay) {
+					assert.False(t, r.End.After(nextDay), "window starting at %s ends on the next day", r.Start)
+				}
+			}
+		})
+	}
+}
+
+func TestRollupHistoricalClimaCell(t *testing.T) {
+	temp := 10.0
+	var s HistoricalClimaCell
+	s.ObservationTime = DateValue{Value: time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)}
+	s.Temp = &FloatValue{Value: &temp, Units: "C"}
+
+	rollups, err := RollupHistoricalClimaCell([]HistoricalClimaCell{s, {}}, RollupOptions{})
+	require.NoError(t, err)
+	require.Len(t, rollups, 1)
+	assert.Equal(t, 1, rollups[0].Samples, "samples without an observation time are skipped")
+	assert.Equal(t, WeatherCode(""), rollups[0].WeatherCode)
+}
+
+func TestRollupPrecipitationSum(t *testing.T) {
+	// an hour of 5-minute samples raining 1.2 mm/hr, so 1.2 mm fell
+	start := time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+	samples := make([]HistoricalClimaCell, 12)
+	for i := range samples {
+		rate := 1.2
+		samples[i].ObservationTime = DateValue{Value: start.Add(time.Duration(i) * 5 * time.Minute)}
+		samples[i].Precipitation = &FloatValue{Value: &rate, Units: "mm/hr"}
+	}
+	// samples don't need to be in order
+	samples[0], samples[11] = samples[11], samples[0]
+
+	rollups, err := RollupHistoricalClimaCell(samples, RollupOptions{})
+	require.NoError(t, err)
+	require.Len(t, rollups, 1)
+	sum, ok := rollups[0].Field(FieldPrecipitation).Sum.GetValue()
+	require.True(t, ok)
+	assert.InDelta(t, 1.2, sum, 1e-9)
+	mean, _ := rollups[0].Field(FieldPrecipitation).Mean.GetValue()
+	assert.InDelta(t, 1.2, mean, 1e-9)
+}
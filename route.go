@@ -0,0 +1,166 @@
+package climacell
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EndpointRoute is the ClimaCell API's endpoint for weather along a route.
+const EndpointRoute Endpoint = "weather/route"
+
+// earthRadius is the Earth's mean radius in meters, for haversine distances.
+const earthRadius = 6371008.8
+
+// RouteWaypoint is a point on a route, and when we expect to arrive there.
+type RouteWaypoint struct {
+	// Location is the coordinates of the waypoint.
+	Location LatLon
+	// ETA is the expected time of arrival at the waypoint.
+	ETA time.Time
+}
+
+// SpeedProfile is the expected speed, in kilometers per hour, for each
+// segment of a Polyline route, where segment i goes from point i to point
+// i+1. If the profile has fewer speeds than the route has segments, the last
+// speed is used for the rest of the route, so a profile with one speed is a
+// constant speed.
+type SpeedProfile []float64
+
+// RouteArgs are the arguments for a request for the weather along a route.
+// Either Waypoints, or Route and Departure, should be set.
+type RouteArgs struct {
+	// Waypoints are the points along the route, in order, with the
+	// expected time of arrival at each one.
+	Waypoints []RouteWaypoint
+	// Route, if Waypoints is empty, is the path of the route. Each point
+	// on the Polyline is a waypoint, with ETAs calculated from Departure
+	// and Speeds by WaypointsAlong.
+	Route Polyline
+	// Departure is when travel along Route starts.
+	Departure time.Time
+	// Speeds is the speed profile for travel along Route.
+	Speeds SpeedProfile
+	// UnitSystem indicates whether we are requesting weather data in SI or
+	// US units of measure. The default is SI.
+	UnitSystem string
+	// Fields indicates which fields we want on the returned weather
+	// samples, such as "temp" or "road_risk".
+	Fields []string
+}
+
+// routeBody is the request body for the /weather/route endpoint.
+type routeBody struct {
+	Waypoints  []routeBodyWaypoint `json:"waypoints"`
+	UnitSystem string              `json:"unit_system,omitempty"`
+	Fields     []string            `json:"fields,omitempty"`
+}
+
+type routeBodyWaypoint struct {
+	LatLon
+	ETA string `json:"eta"`
+}
+
+func (args RouteArgs) waypoints() ([]RouteWaypoint, error) {
+	if len(args.Waypoints) > 0 {
+		if len(args.Route) > 0 {
+			return nil, errors.New("only one of Waypoints and Route can be set")
+		}
+		return args.Waypoints, nil
+	}
+	if len(args.Route) == 0 {
+		return nil, errors.New("either Waypoints or Route is required for route requests")
+	}
+	if args.Departure.IsZero() {
+		return nil, errors.New("a departure time is required for route requests with a Route")
+	}
+	return WaypointsAlong(args.Route, args.Departure, args.Speeds)
+}
+
+// WaypointsAlong returns a waypoint for each point on a route, with ETAs for
+// travel starting from the first point at departure, moving at the speeds in
+// the speed profile. Distances between points are great-circle distances.
+func WaypointsAlong(route Polyline, departure time.Time, speeds SpeedProfile) ([]RouteWaypoint, error) {
+	if err := route.Validate(); err != nil {
+		return nil, errors.WithMessage(err, "invalid route")
+	}
+	if len(speeds) == 0 {
+		return nil, errors.New("the speed profile needs at least one speed")
+	}
+	for i, s := range speeds {
+		if s <= 0 || math.IsInf(s, 0) || math.IsNaN(s) {
+			return nil, errors.Errorf("speed %d is %v; speeds must be positive", i, s)
+		}
+	}
+
+	waypoints := make([]RouteWaypoint, len(route))
+	waypoints[0] = RouteWaypoint{Location: route[0], ETA: departure}
+	eta := departure
+	for i := 1; i < len(route); i++ {
+		speed := speeds[len(speeds)-1]
+		if i-1 < len(speeds) {
+			speed = speeds[i-1]
+		}
+		hours := haversine(route[i-1], route[i]) / 1000 / speed
+		eta = eta.Add(time.Duration(hours * float64(time.Hour)))
+		waypoints[i] = RouteWaypoint{Location: route[i], ETA: eta}
+	}
+	return waypoints, nil
+}
+
+// haversine returns the great-circle distance between two points in meters.
+func haversine(a, b LatLon) float64 {
+	const rad = math.Pi / 180
+	dLat := (b.Lat - a.Lat) * rad
+	dLon := (b.Lon - a.Lon) * rad
+	h := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(a.Lat*rad)*math.Cos(b.Lat*rad)*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// RouteWeather returns the weather at each waypoint along a route at its
+// expected time of arrival on successful requests to the /weather/route
+// endpoint, or an ErrorResponse on a 400, 401, 403, or 500 error. Samples are
+// typed like HourlyForecast samples, including road risk fields, and there is
+// one sample for each waypoint, in order.
+func (c *Client) RouteWeather(ctx context.Context, args RouteArgs) ([]HourlyForecast, error) {
+	waypoints, err := args.waypoints()
+	if err != nil {
+		return nil, err
+	}
+
+	forecastArgs := c.withDefaults(ForecastArgs{UnitSystem: args.UnitSystem, Fields: args.Fields})
+	body := routeBody{
+		Waypoints:  make([]routeBodyWaypoint, len(waypoints)),
+		UnitSystem: forecastArgs.UnitSystem,
+		Fields:     forecastArgs.Fields,
+	}
+	for i, w := range waypoints {
+		if err := validateLatLon(w.Location); err != nil {
+			return nil, errors.WithMessagef(err, "waypoint %d", i)
+		}
+		if w.ETA.IsZero() {
+			return nil, errors.Errorf("waypoint %d has no ETA", i)
+		}
+		body.Waypoints[i] = routeBodyWaypoint{LatLon: w.Location, ETA: w.ETA.Format(time.RFC3339)}
+	}
+
+	var samples []HourlyForecast
+	err = c.call(ctx, &apiRequest{
+		method:   http.MethodPost,
+		endpt:    EndpointRoute,
+		path:     string(EndpointRoute),
+		body:     body,
+		readOnly: true,
+	}, &samples)
+	if err != nil {
+		return nil, err
+	}
+	if len(samples) != len(waypoints) {
+		return nil, errors.Errorf("expected %d route samples, got %d", len(waypoints), len(samples))
+	}
+	return samples, nil
+}
@@ -0,0 +1,121 @@
+package climacell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// routeServer echoes back a weather sample for each waypoint it receives,
+// with the waypoint's coordinates and ETA, and a road risk.
+func routeServer(t *testing.T, got *routeBody) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/weather/route", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(got))
+
+		samples := make([]json.RawMessage, len(got.Waypoints))
+		for i, wp := range got.Waypoints {
+			samples[i] = json.RawMessage(fmt.Sprintf(`{
+				"lat": %v,
+				"lon": %v,
+				"observation_time": {"value": %q},
+				"road_risk": {"value": "moderate_risk"}
+			}`, wp.Lat, wp.Lon, wp.ETA))
+		}
+		json.NewEncoder(w).Encode(samples)
+	}))
+}
+
+func TestRouteWeatherWaypoints(t *testing.T) {
+	var got routeBody
+	srv := routeServer(t, &got)
+	defer srv.Close()
+
+	depart := time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+	c := NewClient("test_api_key", WithBaseURL(srv.URL), WithUnitSystem("us"))
+	samples, err := c.RouteWeather(context.Background(), RouteArgs{
+		Waypoints: []RouteWaypoint{
+			{Location: LatLon{Lat: 42.36, Lon: -71.06}, ETA: depart},
+			{Location: LatLon{Lat: 41.82, Lon: -71.41}, ETA: depart.Add(time.Hour)},
+		},
+		Fields: []string{"road_risk"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "us", got.UnitSystem)
+	assert.Equal(t, []string{"road_risk"}, got.Fields)
+	assert.Equal(t, "2020-09-01T13:00:00Z", got.Waypoints[1].ETA)
+
+	require.Len(t, samples, 2)
+	assert.Equal(t, LatLon{Lat: 41.82, Lon: -71.41}, samples[1].LatLon)
+	assert.Equal(t, depart.Add(time.Hour), samples[1].ObservationTime.Value)
+	risk, ok := samples[1].RoadRisk.GetValue()
+	assert.True(t, ok)
+	assert.Equal(t, RoadRiskModerate, risk)
+}
+
+func TestRouteWeatherPolyline(t *testing.T) {
+	var got routeBody
+	srv := routeServer(t, &got)
+	defer srv.Close()
+
+	depart := time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+	c := NewClient("test_api_key", WithBaseURL(srv.URL))
+	samples, err := c.RouteWeather(context.Background(), RouteArgs{
+		Route:     Polyline{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 1}, {Lat: 0, Lon: 2}},
+		Departure: depart,
+		Speeds:    SpeedProfile{100},
+	})
+	require.NoError(t, err)
+	require.Len(t, samples, 3)
+	require.Len(t, got.Waypoints, 3)
+	assert.Equal(t, "2020-09-01T12:00:00Z", got.Waypoints[0].ETA)
+	assert.Equal(t, "2020-09-01T13:06:43Z", got.Waypoints[1].ETA)
+}
+
+func TestWaypointsAlong(t *testing.T) {
+	depart := time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+	route := Polyline{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 1}, {Lat: 0, Lon: 2}}
+
+	// one degree of longitude at the equator is about 111.2 km
+	wps, err := WaypointsAlong(route, depart, SpeedProfile{111.19, 55.6})
+	require.NoError(t, err)
+	require.Len(t, wps, 3)
+	assert.Equal(t, depart, wps[0].ETA)
+	assert.WithinDuration(t, depart.Add(time.Hour), wps[1].ETA, time.Second)
+	assert.WithinDuration(t, depart.Add(3*time.Hour), wps[2].ETA, 5*time.Second)
+	assert.Equal(t, route[2], wps[2].Location)
+
+	_, err = WaypointsAlong(route, depart, nil)
+	assert.Error(t, err)
+	_, err = WaypointsAlong(route, depart, SpeedProfile{0})
+	assert.Error(t, err)
+	_, err = WaypointsAlong(route[:1], depart, SpeedProfile{50})
+	assert.Error(t, err)
+}
+
+func TestRouteWeatherArgsErrors(t *testing.T) {
+	c := NewClient("test_api_key")
+	ctx := context.Background()
+	depart := time.Now()
+
+	_, err := c.RouteWeather(ctx, RouteArgs{})
+	assert.Error(t, err)
+	_, err = c.RouteWeather(ctx, RouteArgs{
+		Waypoints: []RouteWaypoint{{Location: LatLon{}, ETA: depart}},
+		Route:     Polyline{{}, {Lat: 1}},
+	})
+	assert.Error(t, err, "only one of Waypoints and Route can be set")
+	_, err = c.RouteWeather(ctx, RouteArgs{Route: Polyline{{}, {Lat: 1}}, Speeds: SpeedProfile{50}})
+	assert.Error(t, err, "departure time is required")
+	_, err = c.RouteWeather(ctx, RouteArgs{Waypoints: []RouteWaypoint{{Location: LatLon{}}}})
+	assert.Error(t, err, "waypoints need ETAs")
+}
@@ -0,0 +1,175 @@
+package climacell
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// sampleStream reads the weather samples in a JSON array response body one
+// at a time.
+type sampleStream struct {
+	body io.ReadCloser
+	dec  *json.Decoder
+	// ctx is the request's context, for telling errors from it being done
+	// apart from decode errors.
+	ctx context.Context
+
+	// for recording decode errors
+	c     *Client
+	endpt Endpoint
+
+	// finish logs the request, records its metrics, and ends its span when
+	// the stream is closed, with the error from reading the response, if any.
+	finish func(err error)
+
+	err  error
+	done bool
+}
+
+// open starts reading a response body, which must be a JSON array.
+func (s *sampleStream) open(ctx context.Context, body io.ReadCloser) (decodeFailed bool, err error) {
+	dec := json.NewDecoder(body)
+	tok, err := dec.Token()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return false, errors.WithMessage(ctxErr, "reading response data")
+		}
+		return true, errors.WithMessage(err, "deserializing response data")
+	}
+	if tok != json.Delim('[') {
+		return true, errors.Errorf("expected response data to be a JSON array, got %v", tok)
+	}
+	s.body, s.dec, s.ctx = body, dec, ctx
+	return false, nil
+}
+
+// next deserializes the next sample into v, returning false at the end of the
+// array or on an error.
+func (s *sampleStream) next(v interface{}) bool {
+	if s.done || s.err != nil {
+		return false
+	}
+	if !s.dec.More() {
+		s.done = true
+		s.Close()
+		return false
+	}
+	if err := s.dec.Decode(v); err != nil {
+		if ctxErr := s.ctx.Err(); ctxErr != nil {
+			s.err = errors.WithMessage(ctxErr, "reading response data")
+		} else {
+			s.err = errors.WithMessage(err, "deserializing weather sample")
+			if s.c.metrics != nil {
+				s.c.metrics.IncDecodeErrors(s.endpt)
+			}
+		}
+		s.Close()
+		return false
+	}
+	return true
+}
+
+// Close closes the response body, and logs the request.
+func (s *sampleStream) Close() error {
+	if s.body == nil {
+		return nil
+	}
+	err := s.body.Close()
+	s.body = nil
+	if s.finish != nil {
+		s.finish(s.err)
+	}
+	return err
+}
+
+func (c *Client) openStream(ctx context.Context, endpt Endpoint, args ForecastArgs) (*sampleStream, error) {
+	s := &sampleStream{c: c, endpt: endpt}
+	if err := c.getWeatherSamples(ctx, endpt, args, s); err != nil {
+		return nil, err
+	}
+	if s.body == nil {
+		return nil, errors.Errorf("no response data to stream from %s", endpt)
+	}
+	return s, nil
+}
+
+// NowcastIterator iterates over the samples in a response from the
+// /weather/nowcast endpoint as they are read in. Call Next to advance to each
+// sample, and Sample to get it. After Next returns false, Err returns any
+// error from reading the response. Call Close when you are done with the
+// iterator, even if you stop before Next returns false.
+type NowcastIterator struct {
+	stream *sampleStream
+	cur    NowCastForecast
+}
+
+// NowcastStream is like NowcastWithContext, but rather than reading in the
+// full response before returning, it returns a NowcastIterator that reads in
+// each sample as you iterate, so memory use stays flat on long nowcasts with
+// small timesteps. Streamed responses are not cached, and since the request
+// is complete once the response starts coming in, errors reading the rest of
+// the response are not retried, and are returned from the iterator's Err.
+// The request is logged, its metrics recorded, and its tracing span ended once
+// the iterator is done or closed, so they cover reading in the whole response.
+// Streamed requests also skip the Client's Middleware, since their samples are
+// read in after the request returns.
+func (c *Client) NowcastStream(ctx context.Context, args ForecastArgs) (*NowcastIterator, error) {
+	s, err := c.openStream(ctx, EndpointNowcast, args)
+	if err != nil {
+		return nil, err
+	}
+	return &NowcastIterator{stream: s}, nil
+}
+
+// Next advances the iterator to the next sample, returning false when there
+// are no more samples, or when reading the next sample failed.
+func (it *NowcastIterator) Next() bool {
+	it.cur = NowCastForecast{}
+	return it.stream.next(&it.cur)
+}
+
+// Sample returns the current sample.
+func (it *NowcastIterator) Sample() NowCastForecast { return it.cur }
+
+// Err returns the error from reading the response, if any.
+func (it *NowcastIterator) Err() error { return it.stream.err }
+
+// Close closes the response. It is safe to call more than once.
+func (it *NowcastIterator) Close() error { return it.stream.Close() }
+
+// HourlyForecastIterator iterates over the samples in a response from the
+// /weather/forecast/hourly endpoint as they are read in, like a
+// NowcastIterator.
+type HourlyForecastIterator struct {
+	stream *sampleStream
+	cur    HourlyForecast
+}
+
+// HourlyForecastStream is like HourlyForecastWithContext, but returns an
+// iterator over the samples in the response like NowcastStream.
+func (c *Client) HourlyForecastStream(ctx context.Context, args ForecastArgs) (*HourlyForecastIterator, error) {
+	s, err := c.openStream(ctx, EndpointHourlyForecast, args)
+	if err != nil {
+		return nil, err
+	}
+	return &HourlyForecastIterator{stream: s}, nil
+}
+
+// Next advances the iterator to the next sample, returning false when there
+// are no more samples, or when reading the next sample failed.
+func (it *HourlyForecastIterator) Next() bool {
+	it.cur = HourlyForecast{}
+	return it.stream.next(&it.cur)
+}
+
+// Sample returns the current sample.
+func (it *HourlyForecastIterator) Sample() HourlyForecast { return it.cur }
+
+// Err returns the error from reading the response, if any.
+func (it *HourlyForecastIterator) Err() error { return it.stream.err }
+
+// Close closes the response. It is safe to call more than once.
+func (it *HourlyForecastIterator) Close() error { return it.stream.Close() }
@@ -0,0 +1,213 @@
+package climacell
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamServer responds to nowcast and hourly forecast requests with n
+// samples a minute apart, followed by body, which is "]" for a valid
+// response.
+func streamServer(n int, end string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+		samples := make([]string, n)
+		for i := range samples {
+			samples[i] = fmt.Sprintf(
+				`{"lat": 42.3, "lon": -71.1, "temp": {"value": %d, "units": "C"}, "observation_time": {"value": %q}}`,
+				i, start.Add(time.Duration(i)*time.Minute).Format(time.RFC3339),
+			)
+		}
+		w.Write([]byte("[" + strings.Join(samples, ",") + end))
+	}))
+}
+
+func TestNowcastStream(t *testing.T) {
+	srv := streamServer(500, "]")
+	defer srv.Close()
+
+	c := NewClient("test_api_key", WithBaseURL(srv.URL), WithCache(NewLRUCache(10)))
+	it, err := c.NowcastStream(context.Background(), ForecastArgs{Location: LatLon{Lat: 42.3, Lon: -71.1}})
+	require.NoError(t, err)
+	defer it.Close()
+
+	var n int
+	for it.Next() {
+		temp, ok := it.Sample().Temp.GetValue()
+		require.True(t, ok)
+		assert.Equal(t, float64(n), temp)
+		n++
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, 500, n)
+	assert.False(t, it.Next())
+
+	// streamed responses are not cached
+	assert.Equal(t, CacheStats{}, c.CacheStats())
+}
+
+func TestHourlyForecastStream(t *testing.T) {
+	srv := streamServer(3, `, {"temp": "not a temperature"}]`)
+	defer srv.Close()
+
+	c := NewClient("test_api_key", WithBaseURL(srv.URL))
+	it, err := c.HourlyForecastStream(context.Background(), ForecastArgs{Location: LatLon{Lat: 42.3, Lon: -71.1}})
+	require.NoError(t, err)
+	defer it.Close()
+
+	var n int
+	for it.Next() {
+		assert.Equal(t, LatLon{Lat: 42.3, Lon: -71.1}, it.Sample().LatLon)
+		n++
+	}
+	assert.Equal(t, 3, n)
+	assert.Error(t, it.Err(), "the last sample is malformed")
+}
+
+func TestStreamErrors(t *testing.T) {
+	errSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"statusCode": 400, "errorCode": "BadRequest", "message": "lat out of range"}`))
+	}))
+	defer errSrv.Close()
+	c := NewClient("test_api_key", WithBaseURL(errSrv.URL))
+	_, err := c.HourlyForecastStream(context.Background(), ForecastArgs{Location: LatLon{Lat: 91, Lon: 181}})
+	assert.IsType(t, &ErrorResponse{}, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"not": "an array"}`))
+	}))
+	defer srv.Close()
+	c = NewClient("test_api_key", WithBaseURL(srv.URL))
+	_, err = c.NowcastStream(context.Background(), ForecastArgs{Location: LatLon{Lat: 42.3, Lon: -71.1}})
+	assert.Error(t, err)
+}
+
+func TestStreamSkipsMiddleware(t *testing.T) {
+	srv := streamServer(3, "]")
+	defer srv.Close()
+
+	var calls int
+	doubleTemps := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request, result interface{}) error {
+			calls++
+			if err := next(ctx, req, result); err != nil {
+				return err
+			}
+			for _, s := range *result.(*[]HourlyForecast) {
+				*s.Temp.Value *= 2
+			}
+			return nil
+		}
+	}
+
+	c := NewClient("test_api_key", WithBaseURL(srv.URL), WithMiddleware(doubleTemps))
+	it, err := c.HourlyForecastStream(context.Background(), ForecastArgs{Location: LatLon{Lat: 42.3, Lon: -71.1}})
+	require.NoError(t, err)
+	defer it.Close()
+
+	var n int
+	for it.Next() {
+		n++
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, 3, n)
+	assert.Equal(t, 0, calls)
+
+	forecast, err := c.HourlyForecast(ForecastArgs{Location: LatLon{Lat: 42.3, Lon: -71.1}})
+	require.NoError(t, err)
+	assert.Len(t, forecast, 3)
+	assert.Equal(t, 1, calls)
+}
+
+func TestStreamLogging(t *testing.T) {
+	srv := streamServer(100, "]")
+	defer srv.Close()
+
+	logger := &recordingLogger{}
+	c := NewClient("test_api_key", WithBaseURL(srv.URL), WithLogger(logger))
+	args := ForecastArgs{Location: LatLon{Lat: 42.3, Lon: -71.1}}
+
+	it, err := c.NowcastStream(context.Background(), args)
+	require.NoError(t, err)
+	assert.Empty(t, logger.logs, "streamed requests are logged once they're done")
+	for it.Next() {
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, logger.logs, 1)
+	full := logger.logs[0]
+	assert.Equal(t, http.StatusOK, full.StatusCode)
+	assert.NoError(t, full.Err)
+
+	// closing the iterator doesn't log the request again
+	require.NoError(t, it.Close())
+	assert.Len(t, logger.logs, 1)
+
+	it, err = c.NowcastStream(context.Background(), args)
+	require.NoError(t, err)
+	require.True(t, it.Next())
+	require.NoError(t, it.Close())
+	require.Len(t, logger.logs, 2)
+	assert.True(t, logger.logs[1].BytesReceived < full.BytesReceived)
+}
+
+func TestStreamContextCanceled(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"lat": 42.3, "lon": -71.1}, `))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	m := NewPrometheusMetrics()
+	c := NewClient("test_api_key", WithBaseURL(srv.URL), WithMetrics(m))
+	ctx, cancel := context.WithCancel(context.Background())
+	it, err := c.NowcastStream(ctx, ForecastArgs{Location: LatLon{Lat: 42.3, Lon: -71.1}})
+	require.NoError(t, err)
+	defer it.Close()
+
+	require.True(t, it.Next())
+	cancel()
+	assert.False(t, it.Next())
+	assert.Equal(t, context.Canceled, errors.Cause(it.Err()))
+	assert.Contains(t, it.Err().Error(), "reading response data")
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.NotContains(t, rec.Body.String(), `climacell_decode_errors_total{endpoint="weather/nowcast"}`)
+}
+
+func TestStreamTracing(t *testing.T) {
+	srv := streamServer(3, `, {"temp": "not a temperature"}]`)
+	defer srv.Close()
+
+	tracer := &fakeTracer{}
+	c := NewClient("test_api_key", WithBaseURL(srv.URL), WithTracer(tracer))
+	it, err := c.HourlyForecastStream(context.Background(), ForecastArgs{Location: LatLon{Lat: 42.3, Lon: -71.1}})
+	require.NoError(t, err)
+	defer it.Close()
+
+	require.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	assert.False(t, span.ended, "streamed requests' spans end once they're done")
+	for it.Next() {
+	}
+	require.Error(t, it.Err())
+	assert.True(t, span.ended)
+	assert.Equal(t, it.Err(), span.err)
+	assert.Equal(t, 200, span.attrs[AttrStatusCode])
+}
@@ -0,0 +1,213 @@
+package climacell
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EndpointTimelines is v4 of the ClimaCell API's endpoint for weather data
+// timelines.
+const EndpointTimelines Endpoint = "timelines"
+
+// The timesteps that can be requested from the Timelines endpoint.
+const (
+	TimestepCurrent  = "current"
+	Timestep1Minute  = "1m"
+	Timestep5Minute  = "5m"
+	Timestep15Minute = "15m"
+	Timestep30Minute = "30m"
+	Timestep1Hour    = "1h"
+	Timestep1Day     = "1d"
+)
+
+// TimelinesArgs are the arguments for a request to v4 of the ClimaCell API's
+// /timelines endpoint.
+type TimelinesArgs struct {
+	// Location sets the location we are requesting weather data for. It
+	// is required.
+	Location Location
+	// Fields indicates which fields we want in each interval, such as
+	// "temperature" or "windGust". Note that v4 field names are in
+	// camelCase, unlike in v3. At least one field is required.
+	Fields []string
+	// Timesteps indicates which timelines to return, such as Timestep1Hour
+	// and Timestep1Day. At least one timestep is required.
+	Timesteps []string
+	// Start and End, if nonzero, indicate the time range we are requesting
+	// weather data for.
+	Start time.Time
+	End   time.Time
+	// Units indicates whether we are requesting weather data in "metric"
+	// or "imperial" units. The default is metric.
+	Units string
+	// Timezone, if nonempty, is the IANA time zone, like
+	// "America/New_York", that daily timesteps are aligned to.
+	Timezone string
+}
+
+// timelinesBody is the request body for the /timelines endpoint.
+type timelinesBody struct {
+	Location  interface{} `json:"location"`
+	Fields    []string    `json:"fields"`
+	Timesteps []string    `json:"timesteps"`
+	StartTime string      `json:"startTime,omitempty"`
+	EndTime   string      `json:"endTime,omitempty"`
+	Units     string      `json:"units,omitempty"`
+	Timezone  string      `json:"timezone,omitempty"`
+}
+
+func (args TimelinesArgs) body() (timelinesBody, error) {
+	if args.Location == nil {
+		return timelinesBody{}, errors.New("a location is required for timelines requests")
+	}
+	loc, err := v4Location(args.Location)
+	if err != nil {
+		return timelinesBody{}, err
+	}
+
+	b := timelinesBody{
+		Location:  loc,
+		Fields:    args.Fields,
+		Timesteps: args.Timesteps,
+		Units:     args.Units,
+		Timezone:  args.Timezone,
+	}
+	if !args.Start.IsZero() {
+		b.StartTime = args.Start.Format(time.RFC3339)
+	}
+	if !args.End.IsZero() {
+		b.EndTime = args.End.Format(time.RFC3339)
+	}
+	return b, nil
+}
+
+// v4Location converts a Location to how locations are sent to v4 of the API,
+// which is either a "lat,lon" string, a location ID, or a GeoJSON geometry.
+func v4Location(loc Location) (interface{}, error) {
+	q := loc.LocationQueryParams()
+	if id := q.Get("location_id"); id != "" {
+		return id, nil
+	}
+	if lat, lon := q.Get("lat"), q.Get("lon"); lat != "" && lon != "" {
+		return lat + "," + lon, nil
+	}
+	if geo := q.Get("location"); geo != "" {
+		if json.Valid([]byte(geo)) {
+			return json.RawMessage(geo), nil
+		}
+		return geo, nil
+	}
+	return nil, errors.Errorf("location of type %T has no query parameters for a location", loc)
+}
+
+// Timeline is the weather data for a single timestep returned from the
+// /timelines endpoint, such as an hourly timeline.
+type Timeline struct {
+	// Timestep is the timestep of this timeline, such as "1h".
+	Timestep string `json:"timestep"`
+	// StartTime and EndTime are the time range this timeline covers.
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	// Intervals contains the weather data at each timestep.
+	Intervals []Interval `json:"intervals"`
+}
+
+// Interval contains the weather data for a single timestep on a Timeline.
+type Interval struct {
+	// StartTime is the time this interval starts.
+	StartTime time.Time `json:"startTime"`
+	// Values contains the requested fields' values for this interval.
+	Values TimelineValues `json:"values"`
+}
+
+// TimelineValues contains the values of the fields on an Interval, keyed by
+// field name. Unlike in v3 of the API, values are flat, rather than objects
+// with a value and units.
+type TimelineValues map[string]json.RawMessage
+
+// GetFloat returns a field's value as a float and a true "ok" if present, or
+// returns 0.0 and false "ok" if the field is absent, null, or not a number.
+func (v TimelineValues) GetFloat(field string) (val float64, ok bool) {
+	var f *float64
+	if err := json.Unmarshal(v[field], &f); err != nil || f == nil {
+		return 0.0, false
+	}
+	return *f, true
+}
+
+// GetInt returns a field's value as an integer and a true "ok" if present,
+// such as for enum fields like "weatherCode", or returns 0 and false "ok" if
+// the field is absent, null, or not an integer.
+func (v TimelineValues) GetInt(field string) (val int, ok bool) {
+	var i *int
+	if err := json.Unmarshal(v[field], &i); err != nil || i == nil {
+		return 0, false
+	}
+	return *i, true
+}
+
+// GetString returns a field's value as a string and a true "ok" if present,
+// or returns a blank string and false "ok" if the field is absent, null, or
+// not a string.
+func (v TimelineValues) GetString(field string) (val string, ok bool) {
+	var s *string
+	if err := json.Unmarshal(v[field], &s); err != nil || s == nil {
+		return "", false
+	}
+	return *s, true
+}
+
+type timelinesResponse struct {
+	Data struct {
+		Timelines []Timeline `json:"timelines"`
+	} `json:"data"`
+}
+
+// Timelines returns weather data timelines on successful requests to v4 of
+// the ClimaCell API's /timelines endpoint, with one Timeline for each
+// requested timestep, or returns an ErrorResponse on a 400, 401, 403, 404, or
+// 500 error. For ErrorResponses from v4 of the API, ErrorCode contains the
+// numeric error code from the response.
+func (c *Client) Timelines(ctx context.Context, args TimelinesArgs) ([]Timeline, error) {
+	body, err := args.body()
+	if err != nil {
+		return nil, err
+	}
+
+	var res timelinesResponse
+	err = c.call(ctx, &apiRequest{
+		method:   http.MethodPost,
+		endpt:    EndpointTimelines,
+		path:     string(EndpointTimelines),
+		body:     body,
+		v4:       true,
+		readOnly: true,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Data.Timelines, nil
+}
+
+// v4ErrorResponse is the format of error responses from v4 of the API.
+type v4ErrorResponse struct {
+	Code    int    `json:"code"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+func (errRes v4ErrorResponse) toErrorResponse(statusCode int) *ErrorResponse {
+	converted := &ErrorResponse{StatusCode: statusCode, Message: errRes.Message}
+	if errRes.Code != 0 {
+		converted.ErrorCode = strconv.Itoa(errRes.Code)
+	}
+	if converted.Message == "" {
+		converted.Message = errRes.Type
+	}
+	return converted
+}
@@ -0,0 +1,110 @@
+package climacell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var timelinesResponseBody = `{
+  "data": {
+    "timelines": [
+      {
+        "timestep": "1h",
+        "startTime": "2020-05-01T00:00:00Z",
+        "endTime": "2020-05-01T01:00:00Z",
+        "intervals": [
+          {
+            "startTime": "2020-05-01T00:00:00Z",
+            "values": {"temperature": 11.23, "weatherCode": 1100, "precipitationIntensity": null}
+          },
+          {
+            "startTime": "2020-05-01T01:00:00Z",
+            "values": {"temperature": 12.8, "weatherCode": 1000, "precipitationIntensity": 0}
+          }
+        ]
+      }
+    ]
+  }
+}`
+
+func TestTimelines(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		fmt.Fprint(w, timelinesResponseBody)
+	}))
+	defer server.Close()
+
+	client := NewClient("test_api_key", WithV4BaseURL(server.URL+"/v4"))
+	timelines, err := client.Timelines(context.Background(), TimelinesArgs{
+		Location:  LatLon{Lat: 42.3826, Lon: -71.146},
+		Fields:    []string{"temperature", "weatherCode", "precipitationIntensity"},
+		Timesteps: []string{Timestep1Hour},
+		Start:     time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+		Units:     "imperial",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/v4/timelines", gotPath)
+	assert.Equal(t, map[string]interface{}{
+		"location":  "42.3826,-71.146",
+		"fields":    []interface{}{"temperature", "weatherCode", "precipitationIntensity"},
+		"timesteps": []interface{}{"1h"},
+		"startTime": "2020-05-01T00:00:00Z",
+		"units":     "imperial",
+	}, gotBody)
+
+	require.Len(t, timelines, 1)
+	tl := timelines[0]
+	assert.Equal(t, Timestep1Hour, tl.Timestep)
+	require.Len(t, tl.Intervals, 2)
+
+	if temp, ok := tl.Intervals[0].Values.GetFloat("temperature"); assert.True(t, ok) {
+		assert.EqualValues(t, 11.23, temp)
+	}
+	if code, ok := tl.Intervals[0].Values.GetInt("weatherCode"); assert.True(t, ok) {
+		assert.Equal(t, 1100, code)
+	}
+	_, ok := tl.Intervals[0].Values.GetFloat("precipitationIntensity")
+	assert.False(t, ok)
+	_, ok = tl.Intervals[0].Values.GetFloat("humidity")
+	assert.False(t, ok)
+	if precip, ok := tl.Intervals[1].Values.GetFloat("precipitationIntensity"); assert.True(t, ok) {
+		assert.EqualValues(t, 0, precip)
+	}
+}
+
+func TestTimelinesErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"code": 400001, "type": "Invalid Body Parameters", "message": "The entries provided as body parameters were not valid."}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test_api_key", WithV4BaseURL(server.URL))
+	_, err := client.Timelines(context.Background(), TimelinesArgs{
+		Location:  LocationID("abc"),
+		Fields:    []string{"temperature"},
+		Timesteps: []string{Timestep1Day},
+	})
+	if errRes, ok := err.(*ErrorResponse); assert.True(t, ok, "expected an ErrorResponse, got %v", err) {
+		assert.Equal(t, &ErrorResponse{
+			StatusCode: 400,
+			ErrorCode:  "400001",
+			Message:    "The entries provided as body parameters were not valid.",
+		}, errRes)
+	}
+
+	_, err = client.Timelines(context.Background(), TimelinesArgs{Fields: []string{"temperature"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,117 @@
+package climacell
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Tracer starts spans for distributed tracing, so that calls to the ClimaCell
+// API show up in traces. It is meant to be a thin adapter over a tracing
+// library such as OpenTelemetry.
+type Tracer interface {
+	// StartSpan starts a span with the given name as a child of any span
+	// in ctx, and returns a context containing the new span.
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+	// Inject adds the trace context in ctx to the headers of an outgoing
+	// request, such as in a W3C traceparent header.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a single span started by a Tracer.
+type Span interface {
+	// SetAttribute sets an attribute on the span.
+	SetAttribute(key string, value interface{})
+	// RecordError records that the operation the span is for failed.
+	RecordError(err error)
+	// End ends the span.
+	End()
+}
+
+// The attributes set on spans for calls to the ClimaCell API.
+const (
+	// AttrEndpoint is the endpoint the call was for.
+	AttrEndpoint = "climacell.endpoint"
+	// AttrLocationType is the kind of Location the call was for, like
+	// "lat_lon" or "location_id".
+	AttrLocationType = "climacell.location_type"
+	// AttrFieldCount is the number of fields requested.
+	AttrFieldCount = "climacell.field_count"
+	// AttrStartTime and AttrEndTime are the start and end of the time range
+	// requested, in RFC3339 format, if the request had them.
+	AttrStartTime = "climacell.start_time"
+	AttrEndTime   = "climacell.end_time"
+	// AttrStatusCode is the HTTP status code of the last response received
+	// for the call.
+	AttrStatusCode = "http.status_code"
+	// AttrErrorCode is the ErrorCode of the ErrorResponse the call failed
+	// with, if any.
+	AttrErrorCode = "climacell.error_code"
+)
+
+// WithTracer sets the Tracer the Client starts a span with for each call to
+// one of its endpoint methods. Retries of a request are part of the same span.
+// By default, requests are not traced.
+func WithTracer(t Tracer) Option {
+	return func(c *Client) { c.tracer = t }
+}
+
+type spanContextKey struct{}
+
+func spanFromContext(ctx context.Context) Span {
+	span, _ := ctx.Value(spanContextKey{}).(Span)
+	return span
+}
+
+// startSpan starts the span for a call to an endpoint.
+func (c *Client) startSpan(ctx context.Context, endpt Endpoint) (context.Context, Span) {
+	ctx, span := c.tracer.StartSpan(ctx, "climacell "+string(endpt))
+	span.SetAttribute(AttrEndpoint, string(endpt))
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// setForecastArgsAttributes sets the attributes for a request for weather
+// data on its span.
+func setForecastArgsAttributes(span Span, args ForecastArgs) {
+	span.SetAttribute(AttrLocationType, locationType(args.Location))
+	span.SetAttribute(AttrFieldCount, len(args.Fields))
+	if !args.Start.IsZero() {
+		span.SetAttribute(AttrStartTime, args.Start.Format(time.RFC3339))
+	}
+	if !args.End.IsZero() {
+		span.SetAttribute(AttrEndTime, args.End.Format(time.RFC3339))
+	}
+}
+
+// endSpan records the error a request failed with, if any, and ends its span.
+func endSpan(span Span, err error) {
+	if err != nil {
+		if errRes, ok := errors.Cause(err).(*ErrorResponse); ok && errRes.ErrorCode != "" {
+			span.SetAttribute(AttrErrorCode, errRes.ErrorCode)
+		}
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// locationType returns the name of the kind of Location a location is, for
+// labeling spans.
+func locationType(loc Location) string {
+	switch loc.(type) {
+	case nil:
+		return "none"
+	case LatLon, *LatLon:
+		return "lat_lon"
+	case LocationID, *LocationID:
+		return "location_id"
+	case Polygon:
+		return "polygon"
+	case Polyline:
+		return "polyline"
+	default:
+		return fmt.Sprintf("%T", loc)
+	}
+}
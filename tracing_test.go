@@ -0,0 +1,85 @@
+package climacell
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSpan struct {
+	name  string
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+func (s *fakeSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *fakeSpan) RecordError(err error)                      { s.err = err }
+func (s *fakeSpan) End()                                       { s.ended = true }
+
+type fakeTracer struct{ spans []*fakeSpan }
+
+func (t *fakeTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	s := &fakeSpan{name: name, attrs: make(map[string]interface{})}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+func (t *fakeTracer) Inject(ctx context.Context, header http.Header) {
+	header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+}
+
+func TestTracingSpans(t *testing.T) {
+	var gotTraceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTraceparent = r.Header.Get("traceparent")
+		if r.URL.Query().Get("location_id") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"statusCode": 400, "errorCode": "BadRequest", "message": "invalid location"}`)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	tracer := &fakeTracer{}
+	client := NewClient("test_api_key", WithBaseURL(server.URL), WithTracer(tracer))
+
+	start := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	_, err := client.HourlyForecast(ForecastArgs{
+		Location: LatLon{Lat: 11.3, Lon: 52.4},
+		Fields:   []string{"temp", "humidity"},
+		Start:    start,
+	})
+	require.NoError(t, err)
+	_, err = client.Nowcast(ForecastArgs{Location: LocationID("abc")})
+	require.Error(t, err)
+
+	require.Len(t, tracer.spans, 2)
+	ok := tracer.spans[0]
+	assert.Equal(t, "climacell weather/forecast/hourly", ok.name)
+	assert.Equal(t, map[string]interface{}{
+		AttrEndpoint:     "weather/forecast/hourly",
+		AttrLocationType: "lat_lon",
+		AttrFieldCount:   2,
+		AttrStartTime:    "2020-05-01T00:00:00Z",
+		AttrStatusCode:   200,
+	}, ok.attrs)
+	assert.NoError(t, ok.err)
+	assert.True(t, ok.ended)
+
+	failed := tracer.spans[1]
+	assert.Equal(t, "location_id", failed.attrs[AttrLocationType])
+	assert.Equal(t, 400, failed.attrs[AttrStatusCode])
+	assert.Equal(t, "BadRequest", failed.attrs[AttrErrorCode])
+	assert.Equal(t, err, failed.err)
+	assert.True(t, failed.ended)
+
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", gotTraceparent)
+}
@@ -0,0 +1,191 @@
+// Package units converts weather data between units of measure, such as from
+// degrees Celsius to degrees Fahrenheit, or from hectopascals to inches of
+// mercury.
+package units
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Dimension is the kind of quantity a Unit measures, such as temperature.
+// Values can only be converted between units of the same dimension.
+type Dimension string
+
+// The dimensions of the units weather data is in.
+const (
+	Temperature       Dimension = "temperature"
+	Speed             Dimension = "speed"
+	Pressure          Dimension = "pressure"
+	Distance          Dimension = "distance"
+	PrecipitationRate Dimension = "precipitation rate"
+	Accumulation      Dimension = "accumulation"
+	Irradiance        Dimension = "irradiance"
+)
+
+// Unit is a unit of measure. Its value is the unit's symbol in the ClimaCell
+// API, such as "C" or "m/s", so a Unit can be converted to the string in a
+// FloatValue's Units.
+type Unit string
+
+// Temperature units.
+const (
+	Celsius    Unit = "C"
+	Fahrenheit Unit = "F"
+	Kelvin     Unit = "K"
+)
+
+// Speed units.
+const (
+	MetersPerSecond   Unit = "m/s"
+	KilometersPerHour Unit = "km/h"
+	MilesPerHour      Unit = "mph"
+	Knots             Unit = "kn"
+)
+
+// Pressure units.
+const (
+	Hectopascals    Unit = "hPa"
+	Millibars       Unit = "mbar"
+	InchesOfMercury Unit = "inHg"
+	MillimetersOfHg Unit = "mmHg"
+	Kilopascals     Unit = "kPa"
+	PoundsPerSqInch Unit = "psi"
+)
+
+// Distance units.
+const (
+	Meters     Unit = "m"
+	Kilometers Unit = "km"
+	Feet       Unit = "ft"
+	Miles      Unit = "mi"
+)
+
+// Precipitation rate units.
+const (
+	MillimetersPerHour Unit = "mm/hr"
+	InchesPerHour      Unit = "in/hr"
+)
+
+// Precipitation accumulation units.
+const (
+	Millimeters Unit = "mm"
+	Centimeters Unit = "cm"
+	Inches      Unit = "in"
+)
+
+// Irradiance units.
+const (
+	WattsPerSquareMeter      Unit = "w/sqm"
+	BTUsPerSquareFootPerHour Unit = "btu/sqft"
+)
+
+// unitInfo is how to convert a unit to and from its dimension's base unit,
+// where a value v in the unit is v*scale + offset in the base unit.
+type unitInfo struct {
+	dim    Dimension
+	scale  float64
+	offset float64
+}
+
+// The base units are Celsius, meters per second, hectopascals, meters,
+// millimeters per hour, millimeters, and watts per square meter.
+var unitInfos = map[Unit]unitInfo{
+	Celsius:    {dim: Temperature, scale: 1},
+	Fahrenheit: {dim: Temperature, scale: 5.0 / 9, offset: -32 * 5.0 / 9},
+	Kelvin:     {dim: Temperature, scale: 1, offset: -273.15},
+
+	MetersPerSecond:   {dim: Speed, scale: 1},
+	KilometersPerHour: {dim: Speed, scale: 1000.0 / 3600},
+	MilesPerHour:      {dim: Speed, scale: 0.44704},
+	Knots:             {dim: Speed, scale: 1852.0 / 3600},
+
+	Hectopascals:    {dim: Pressure, scale: 1},
+	Millibars:       {dim: Pressure, scale: 1},
+	InchesOfMercury: {dim: Pressure, scale: 33.8638866667},
+	MillimetersOfHg: {dim: Pressure, scale: 1.33322387415},
+	Kilopascals:     {dim: Pressure, scale: 10},
+	PoundsPerSqInch: {dim: Pressure, scale: 68.9475729318},
+
+	Meters:     {dim: Distance, scale: 1},
+	Kilometers: {dim: Distance, scale: 1000},
+	Feet:       {dim: Distance, scale: 0.3048},
+	Miles:      {dim: Distance, scale: 1609.344},
+
+	MillimetersPerHour: {dim: PrecipitationRate, scale: 1},
+	InchesPerHour:      {dim: PrecipitationRate, scale: 25.4},
+
+	Millimeters: {dim: Accumulation, scale: 1},
+	Centimeters: {dim: Accumulation, scale: 10},
+	Inches:      {dim: Accumulation, scale: 25.4},
+
+	WattsPerSquareMeter:      {dim: Irradiance, scale: 1},
+	BTUsPerSquareFootPerHour: {dim: Irradiance, scale: 3.15459075},
+}
+
+// aliases are other ways units are written, keyed by their lowercase form.
+var aliases = map[string]Unit{
+	"°c":         Celsius,
+	"celsius":    Celsius,
+	"°f":         Fahrenheit,
+	"fahrenheit": Fahrenheit,
+	"kelvin":     Kelvin,
+	"kph":        KilometersPerHour,
+	"kmh":        KilometersPerHour,
+	"knots":      Knots,
+	"kt":         Knots,
+	"mb":         Millibars,
+	"in/h":       InchesPerHour,
+	"mm/h":       MillimetersPerHour,
+	"w/m2":       WattsPerSquareMeter,
+	"w/m^2":      WattsPerSquareMeter,
+	"btu/ft2/h":  BTUsPerSquareFootPerHour,
+}
+
+// Parse returns the Unit for a unit string, such as a FloatValue's Units.
+// Units are matched case-insensitively, so "hpa" is Hectopascals, and common
+// alternate spellings like "°F" and "kph" are recognized.
+func Parse(s string) (Unit, error) {
+	trimmed := strings.TrimSpace(s)
+	if _, ok := unitInfos[Unit(trimmed)]; ok {
+		return Unit(trimmed), nil
+	}
+
+	lower := strings.ToLower(trimmed)
+	for u := range unitInfos {
+		if strings.ToLower(string(u)) == lower {
+			return u, nil
+		}
+	}
+	if u, ok := aliases[lower]; ok {
+		return u, nil
+	}
+	return "", errors.Errorf("unknown unit %q", s)
+}
+
+// Dimension returns the kind of quantity the unit measures, or a blank
+// Dimension if the unit is unknown.
+func (u Unit) Dimension() Dimension { return unitInfos[u].dim }
+
+// Convert converts a value from one unit to another, returning an error if
+// either unit is unknown, or if they measure different dimensions.
+func Convert(v float64, from, to Unit) (float64, error) {
+	fromInfo, ok := unitInfos[from]
+	if !ok {
+		return 0, errors.Errorf("unknown unit %q", from)
+	}
+	toInfo, ok := unitInfos[to]
+	if !ok {
+		return 0, errors.Errorf("unknown unit %q", to)
+	}
+	if fromInfo.dim != toInfo.dim {
+		return 0, errors.Errorf("cannot convert %s (%s) to %s (%s)", from, fromInfo.dim, to, toInfo.dim)
+	}
+	if from == to {
+		return v, nil
+	}
+
+	base := v*fromInfo.scale + fromInfo.offset
+	return (base - toInfo.offset) / toInfo.scale, nil
+}
@@ -0,0 +1,65 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Unit
+	}{
+		{"C", Celsius},
+		{"F", Fahrenheit},
+		{"°F", Fahrenheit},
+		{"m/s", MetersPerSecond},
+		{"MPH", MilesPerHour},
+		{"hpa", Hectopascals},
+		{"inHg", InchesOfMercury},
+		{"mm/hr", MillimetersPerHour},
+		{" km ", Kilometers},
+		{"w/sqm", WattsPerSquareMeter},
+	}
+	for _, tc := range tests {
+		u, err := Parse(tc.in)
+		if assert.NoError(t, err, tc.in) {
+			assert.Equal(t, tc.want, u, tc.in)
+		}
+	}
+
+	_, err := Parse("%")
+	assert.Error(t, err)
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		v        float64
+		from, to Unit
+		want     float64
+	}{
+		{100, Celsius, Fahrenheit, 212},
+		{-40, Fahrenheit, Celsius, -40},
+		{0, Celsius, Kelvin, 273.15},
+		{50, Fahrenheit, Kelvin, 283.15},
+		{10, MetersPerSecond, KilometersPerHour, 36},
+		{60, MilesPerHour, KilometersPerHour, 96.56064},
+		{1013.25, Hectopascals, InchesOfMercury, 29.9212},
+		{1, Miles, Kilometers, 1.609344},
+		{1, InchesPerHour, MillimetersPerHour, 25.4},
+		{10, Millimeters, Inches, 0.393701},
+		{1, BTUsPerSquareFootPerHour, WattsPerSquareMeter, 3.15459},
+	}
+	for _, tc := range tests {
+		got, err := Convert(tc.v, tc.from, tc.to)
+		require.NoError(t, err)
+		assert.InDelta(t, tc.want, got, 1e-4, "%v %s to %s", tc.v, tc.from, tc.to)
+	}
+
+	_, err := Convert(1, Celsius, MetersPerSecond)
+	assert.Error(t, err)
+	_, err = Convert(1, Unit("furlongs"), Meters)
+	assert.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package climacell
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timeNow returns the current time. It is a variable so tests can stub it.
+var timeNow = time.Now
+
+// forecastHorizons are how far into the future each forecast endpoint can
+// return weather data for.
+var forecastHorizons = map[Endpoint]time.Duration{
+	EndpointNowcast:        6 * time.Hour,
+	EndpointHourlyForecast: 96 * time.Hour,
+	EndpointDailyForecast:  15 * 24 * time.Hour,
+}
+
+// The range of timesteps, in minutes, the nowcast and historical/climacell
+// endpoints allow.
+const (
+	MinTimestep = 1
+	MaxTimestep = 60
+)
+
+// ValidationProblem is a single problem with a ForecastArgs.
+type ValidationProblem struct {
+	// Arg is the name of the ForecastArgs field with the problem, such as
+	// "Timestep".
+	Arg string
+	// Message describes the problem.
+	Message string
+}
+
+func (p ValidationProblem) String() string { return p.Arg + ": " + p.Message }
+
+// ValidationError is the error returned from validating a ForecastArgs that
+// an endpoint would reject, listing every problem with it.
+type ValidationError struct {
+	// Endpoint is the endpoint the ForecastArgs were validated for.
+	Endpoint Endpoint
+	// Problems are the problems with the ForecastArgs.
+	Problems []ValidationProblem
+}
+
+func (err *ValidationError) Error() string {
+	problems := make([]string, len(err.Problems))
+	for i, p := range err.Problems {
+		problems[i] = p.String()
+	}
+	return fmt.Sprintf("invalid arguments for %s: %s", err.Endpoint, strings.Join(problems, "; "))
+}
+
+// Validate checks a ForecastArgs for problems that would make an endpoint
+// respond with a 400 error, so they can be caught without a request to the
+// API. It checks that there is a location, and that it is a point rather than
+// a Polygon or Polyline, which are only supported by v4 endpoints, that Start
+// is before End and both are within the endpoint's time range, that Timestep
+// is only set for endpoints that take it and is in range, that UnitSystem is
+// "si" or "us" if set, and that the endpoint supports each of the Fields.
+// Fields that aren't one of the Field constants aren't checked, since the API
+// might support fields this package doesn't know about.
+//
+// If there are any problems, the error is a *ValidationError listing all of
+// them. The Client validates ForecastArgs before sending requests for weather
+// data, so requests with invalid ForecastArgs return this error.
+func (args ForecastArgs) Validate(endpt Endpoint) error {
+	err := &ValidationError{Endpoint: endpt}
+	problem := func(arg, format string, a ...interface{}) {
+		err.Problems = append(err.Problems, ValidationProblem{Arg: arg, Message: fmt.Sprintf(format, a...)})
+	}
+
+	switch endpt {
+	case EndpointNowcast, EndpointHourlyForecast, EndpointDailyForecast,
+		EndpointHistoricalStation, EndpointHistoricalClimaCell, EndpointRealTime:
+	default:
+		problem("Endpoint", "%s is not a weather data endpoint", endpt)
+		return err
+	}
+
+	switch args.Location.(type) {
+	case nil:
+		problem("Location", "a location is required")
+	case Polygon, Polyline:
+		problem("Location", "%T locations are only supported by v4 endpoints", args.Location)
+	}
+
+	now := timeNow()
+	if !args.Start.IsZero() && !args.End.IsZero() && !args.End.After(args.Start) {
+		problem("End", "end time %s is not after start time %s",
+			args.End.Format(time.RFC3339), args.Start.Format(time.RFC3339))
+	}
+	if horizon, ok := forecastHorizons[endpt]; ok {
+		limit := now.Add(horizon)
+		for _, t := range []struct {
+			arg string
+			t   time.Time
+		}{{"Start", args.Start}, {"End", args.End}} {
+			if t.t.After(limit) {
+				problem(t.arg, "%s is more than %s in the future", t.t.Format(time.RFC3339), horizon)
+			}
+		}
+	}
+	if endpt == EndpointHistoricalClimaCell || endpt == EndpointHistoricalStation {
+		if args.Start.After(now) {
+			problem("Start", "historical data cannot start in the future")
+		}
+		if args.End.After(now) {
+			problem("End", "historical data cannot end in the future")
+		}
+	}
+
+	if args.Timestep != 0 {
+		if endpt != EndpointNowcast && endpt != EndpointHistoricalClimaCell {
+			problem("Timestep", "timesteps are only supported on %s and %s",
+				EndpointNowcast, EndpointHistoricalClimaCell)
+		} else if args.Timestep < MinTimestep || args.Timestep > MaxTimestep {
+			problem("Timestep", "timestep %d is not between %d and %d minutes",
+				args.Timestep, MinTimestep, MaxTimestep)
+		}
+	}
+
+	if args.UnitSystem != "" && args.UnitSystem != "si" && args.UnitSystem != "us" {
+		problem("UnitSystem", "unit system %q is not \"si\" or \"us\"", args.UnitSystem)
+	}
+
+	for _, fields := range args.Fields {
+		// fields can also be passed in as a comma-separated list
+		for _, f := range strings.Split(fields, ",") {
+			f = strings.TrimSpace(f)
+			// fields this package doesn't know about are left for the
+			// API to check
+			if Field(f).Endpoints() != nil && !Field(f).SupportedBy(endpt) {
+				problem("Fields", "field %q is not supported by %s", f, endpt)
+			}
+		}
+	}
+
+	if len(err.Problems) > 0 {
+		return err
+	}
+	return nil
+}
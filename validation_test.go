@@ -0,0 +1,141 @@
+package climacell
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecastArgsValidate(t *testing.T) {
+	now := time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	loc := LatLon{Lat: 42.3, Lon: -71.1}
+	tests := []struct {
+		name     string
+		endpt    Endpoint
+		args     ForecastArgs
+		wantArgs []string
+	}{
+		{
+			name:  "valid nowcast",
+			endpt: EndpointNowcast,
+			args:  ForecastArgs{Location: loc, End: now.Add(6 * time.Hour), Timestep: 5, UnitSystem: "us"},
+		},
+		{
+			name:  "valid historical with comma-separated fields",
+			endpt: EndpointHistoricalClimaCell,
+			args:  ForecastArgs{Location: loc, Start: now.Add(-time.Hour), End: now, Fields: []string{"temp,road_risk"}},
+		},
+		{
+			name:     "missing location",
+			endpt:    EndpointRealTime,
+			args:     ForecastArgs{},
+			wantArgs: []string{"Location"},
+		},
+		{
+			name:  "polygon location",
+			endpt: EndpointHourlyForecast,
+			args: ForecastArgs{Location: Polygon{{
+				{Lat: 0, Lon: 0}, {Lat: 0, Lon: 1}, {Lat: 1, Lon: 1},
+			}}},
+			wantArgs: []string{"Location"},
+		},
+		{
+			name:     "polyline location",
+			endpt:    EndpointRealTime,
+			args:     ForecastArgs{Location: Polyline{{Lat: 0, Lon: 0}, {Lat: 1, Lon: 1}}},
+			wantArgs: []string{"Location"},
+		},
+		{
+			name:     "end before start",
+			endpt:    EndpointHourlyForecast,
+			args:     ForecastArgs{Location: loc, Start: now.Add(2 * time.Hour), End: now.Add(time.Hour)},
+			wantArgs: []string{"End"},
+		},
+		{
+			name:     "past hourly horizon",
+			endpt:    EndpointHourlyForecast,
+			args:     ForecastArgs{Location: loc, End: now.Add(97 * time.Hour)},
+			wantArgs: []string{"End"},
+		},
+		{
+			name:     "future historical",
+			endpt:    EndpointHistoricalStation,
+			args:     ForecastArgs{Location: loc, Start: now.Add(-time.Hour), End: now.Add(time.Hour)},
+			wantArgs: []string{"End"},
+		},
+		{
+			name:     "timestep on daily forecast",
+			endpt:    EndpointDailyForecast,
+			args:     ForecastArgs{Location: loc, Timestep: 5},
+			wantArgs: []string{"Timestep"},
+		},
+		{
+			name:     "timestep out of range",
+			endpt:    EndpointNowcast,
+			args:     ForecastArgs{Location: loc, Timestep: 90},
+			wantArgs: []string{"Timestep"},
+		},
+		{
+			name:     "every problem is listed",
+			endpt:    EndpointHistoricalStation,
+			args:     ForecastArgs{UnitSystem: "metric", Fields: []string{"temp", "road_risk", "fire_index"}},
+			wantArgs: []string{"Location", "UnitSystem", "Fields", "Fields"},
+		},
+		{
+			name:  "unknown fields are left for the API to check",
+			endpt: EndpointHourlyForecast,
+			args:  ForecastArgs{Location: loc, Fields: []string{"temp", "not_a_modeled_field"}},
+		},
+		{
+			name:     "not a weather endpoint",
+			endpt:    EndpointLocations,
+			args:     ForecastArgs{Location: loc},
+			wantArgs: []string{"Endpoint"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.args.Validate(tc.endpt)
+			if len(tc.wantArgs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.True(t, stderrors.As(err, &validationErr), "got %v", err)
+			assert.Equal(t, tc.endpt, validationErr.Endpoint)
+			var args []string
+			for _, p := range validationErr.Problems {
+				args = append(args, p.Arg)
+			}
+			assert.Equal(t, tc.wantArgs, args)
+		})
+	}
+}
+
+func TestValidationBeforeRequest(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("test_api_key", WithBaseURL(srv.URL))
+	_, err := c.HistoricalStation(ForecastArgs{
+		Location: LatLon{Lat: 42.3, Lon: -71.1},
+		Fields:   []string{string(FieldRoadRisk)},
+	})
+	assert.IsType(t, &ValidationError{}, err)
+	assert.Contains(t, err.Error(), `field "road_risk" is not supported by weather/historical/station`)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&calls))
+}
@@ -28,9 +28,10 @@ type BaseResponseType struct {
 //   and location, therefore returning a null value for the field in the API
 //   response.
 //
-// For convenience, TimeValue, FloatValue, IntValue, and StringValue structs
-// all have GetValue methods so that you can check for data without checking
-// whether two pointer values are non-nil, like this:
+// For convenience, TimeValue, FloatValue, IntValue, and StringValue structs,
+// and enum value structs like WeatherCodeValue, all have GetValue methods so
+// that you can check for data without checking whether two pointer values are
+// non-nil, like this:
 //
 // temp, ok := w.Temp.GetValue()
 // if !ok {
@@ -60,7 +61,7 @@ type WeatherType struct {
 	Precipitation *FloatValue `json:"precipitation,omitempty"`
 	// The type of precipitation for this weather sample. Values include
 	// "none", "rain", "snow", "ice pellets", and "freezing rain".
-	PrecipitationType *StringValue `json:"precipitation_type,omitempty"`
+	PrecipitationType *PrecipitationTypeValue `json:"precipitation_type,omitempty"`
 	// When this weather sample is from a forecast, the percent probability
 	// of precipitation.
 	PrecipitationProbability *FloatValue `json:"precipitation_probability,omitempty"`
@@ -83,7 +84,7 @@ type WeatherType struct {
 	// The phase of the moon. Values include "new_moon", "waxing_crescent",
 	// "first_quarter", "waxing_gibbous", "full", "waning_gibbous",
 	// "third_quarter", and "waning_crescent"
-	MoonPhase *StringValue `json:"moon_phase"`
+	MoonPhase *MoonPhaseValue `json:"moon_phase"`
 	// A text description of the weather. Possible values include
 	// "freezing_rain_heavy", "freezing_rain", "freezing_rain_light",
 	// "freezing_drizzle", "ice_pellets_heavy", "ice_pellets",
@@ -91,7 +92,7 @@ type WeatherType struct {
 	// "tstorm", "rain_heavy", "rain", "rain_light", "drizzle",
 	// "fog_light", "fog", "cloudy", "mostly_cloudy", "partly_cloudy",
 	// "mostly_clear", and "clear".
-	WeatherCode *StringValue `json:"weather_code"`
+	WeatherCode *WeatherCodeValue `json:"weather_code"`
 }
 
 type AirQualityType struct {
@@ -114,19 +115,19 @@ type AirQualityType struct {
 	EpaAQI *IntValue `json:"epa_aqi"`
 	// Primary pollutant in the air for this weather sample per United
 	// States Environmental Protection Agency standard.
-	EPAPrimaryPollutant *StringValue `json:"epa_primary_pollutant"`
+	EPAPrimaryPollutant *PollutantValue `json:"epa_primary_pollutant"`
 	// Health concern for this weather sample per United States
 	// Environmental Protection Agency standard.
-	EPAHealthConcern *StringValue `json:"epa_health_concern"`
+	EPAHealthConcern *HealthConcernValue `json:"epa_health_concern"`
 	// Air quality index for this weather sample per China Ministry of
 	// Ecology and Environment standard.
 	ChinaAQI *IntValue `json:"china_aqi"`
 	// Primary pollutant in the air for this weather sample per China
 	// Ministry of Ecology and Environment standard.
-	ChinaPrimaryPollutant *StringValue `json:"china_primary_pollutant"`
+	ChinaPrimaryPollutant *PollutantValue `json:"china_primary_pollutant"`
 	// Health concern for this weather sample per China Ministry of Ecology
 	// and Environment standard.
-	ChinaHealthConcern *StringValue `json:"china_health_concern"`
+	ChinaHealthConcern *HealthConcernValue `json:"china_health_concern"`
 }
 
 type FireIndexType struct {
@@ -140,7 +141,7 @@ type RoadRiskType struct {
 	// weather samples in EU and US locations. Possible values include
 	// "low_risk", "moderate_risk", "mod_hi_risk", "high_risk", and
 	// "extreme_risk".
-	RoadRisk *StringValue `json:"road_risk"`
+	RoadRisk *RoadRiskValue `json:"road_risk"`
 	// ClimaCell road risk (EU and US only)
 	RoadRiskScore *StringValue `json:"road_risk_score"`
 	// An integer between 1 and 100 that is indicative of the level of confidence of road risk prediction (EU and US only)
@@ -186,11 +187,6 @@ type HistoricalStation struct {
 	WeatherType
 }
 
-// [TODO] If it can be determined that enum values like moon phase and
-// precipitaiton type don't change their deserialization without the version
-// number also being bumped up, it would be nice to have enums for these values
-// instead of using StringValues.
-
 // StringValue is a field on a Weather returned from the ClimaCell API that is
 // of type string.
 type StringValue struct {
@@ -324,8 +320,8 @@ type ForecastArgs struct {
 	// latitude and longitude coordinates ("lat" and "lon" query
 	// parameters).
 	// A location is the one field that is required for forecast requests;
-	// if it is absent and therefore no location query params are filled,
-	// any request for forecast data will error with a 400.
+	// if it is absent, requests for forecast data return a
+	// ValidationError without being sent.
 	Location Location
 	// Start, if nonzero, indicates the start of the time range we are
 	// requesting weather data for, filling in the "start_time" query parameter.
@@ -338,15 +334,16 @@ type ForecastArgs struct {
 	// parameter. For example if timestep is 5 on the nowcast endpoint, we
 	// are requesting nowcast data for every five minutes.
 	// Only used on the /weather/historical/climacell and /weather/nowcast
-	// endpoints; on other endpoints if this is used, the request will
-	// error with a 400.
+	// endpoints; on other endpoints if this is used, requests return a
+	// ValidationError without being sent.
 	Timestep int
 	// UnitSystem indicates whether we are requesting weather data in SI or
 	// US units of measure, filling in the "unit_system" query parameter.
 	// The default is SI.
 	UnitSystem string
 	// Fields indicates which fields we want on the returned weather
-	// sample, such as "temp", "humidity", etx.
+	// sample, such as "temp", "humidity", etx. FieldsFor converts Field
+	// constants to Fields, checking that the endpoint supports them.
 	Fields []string
 }
 